@@ -1,18 +1,35 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"go-api/authtoken"
 	"go-api/config"
 	"go-api/database"
 	"go-api/email"
+	"go-api/oidc"
+	"go-api/passwordhash"
+	"go-api/permission"
+	"go-api/policy"
 	"go-api/shared/logger"
+	"go-api/shared/validator"
 
+	"github.com/go-webauthn/webauthn/webauthn"
 	"gorm.io/gorm"
 )
 
 type Provider struct {
-	DB    *gorm.DB
-	Email *email.EmailService
+	DB        *gorm.DB
+	Email     *email.EmailService
+	Tokens    authtoken.Strategy
+	Passwords *passwordhash.Hasher
+	OIDC      *oidc.Registry
+	// Permissions resolves the permissions users hold through their role
+	Permissions *permission.Checker
+	// Policies holds the resource-level authorization rules registered by the domains
+	Policies *policy.Registry
+	// WebAuthn is nil when passkeys are not configured
+	WebAuthn *webauthn.WebAuthn
 }
 
 func BootProvider(cfg *config.Config) (*Provider, error) {
@@ -27,10 +44,57 @@ func BootProvider(cfg *config.Config) (*Provider, error) {
 	// Initialize email service as dependency
 	emailService := email.NewEmailService(cfg)
 	logger.Infof("Email service initialized successfully")
+	logger.Infof("Initializing %s token strategy...", cfg.TokenStrategy)
+	tokens, err := authtoken.NewStrategy(db, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize token strategy: %w", err)
+	}
+	logger.Infof("Token strategy initialized successfully")
+	passwords, err := passwordhash.NewHasher(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize password hasher: %w", err)
+	}
+	logger.Infof("Hashing passwords with %s", cfg.PasswordHashAlgorithm)
+	passwordPolicy, err := validator.NewPasswordPolicy(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load password policy: %w", err)
+	}
+	validator.SetPasswordPolicy(passwordPolicy)
+	// Providers are discovered lazily on first login
+	oidcProviders := oidc.NewRegistry(cfg.OIDCProviders)
+	logger.Infof("Registered %d OpenID Connect login providers", len(cfg.OIDCProviders))
+	logger.Infof("Synchronizing permission registry...")
+	if err := permission.Sync(context.Background(), db); err != nil {
+		return nil, fmt.Errorf("failed to synchronize permissions: %w", err)
+	}
+	permissions := permission.NewChecker(db, cfg.PermissionCacheTTL)
+	logger.Infof("Permission registry synchronized successfully")
+	var passkeys *webauthn.WebAuthn
+	if cfg.WebAuthnRPID != "" {
+		passkeys, err = webauthn.New(&webauthn.Config{
+			RPID:          cfg.WebAuthnRPID,
+			RPDisplayName: cfg.WebAuthnRPDisplayName,
+			RPOrigins:     cfg.WebAuthnRPOrigins,
+			Timeouts: webauthn.TimeoutsConfig{
+				Login:        webauthn.TimeoutConfig{Enforce: true, Timeout: cfg.WebAuthnChallengeExpiry, TimeoutUVD: cfg.WebAuthnChallengeExpiry},
+				Registration: webauthn.TimeoutConfig{Enforce: true, Timeout: cfg.WebAuthnChallengeExpiry, TimeoutUVD: cfg.WebAuthnChallengeExpiry},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize WebAuthn: %w", err)
+		}
+		logger.Infof("Passkeys enabled for relying party %s", cfg.WebAuthnRPID)
+	}
 
 	return &Provider{
-		DB:    db,
-		Email: emailService,
+		DB:          db,
+		Email:       emailService,
+		Tokens:      tokens,
+		Passwords:   passwords,
+		OIDC:        oidcProviders,
+		Permissions: permissions,
+		Policies:    policy.NewRegistry(),
+		WebAuthn:    passkeys,
 	}, nil
 }
 
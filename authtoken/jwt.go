@@ -0,0 +1,260 @@
+package authtoken
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"go-api/config"
+	"go-api/model"
+	"go-api/repository"
+	"go-api/shared/securetoken"
+	"go-api/shared/timezone"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+func init() {
+	// Millisecond precision for iat/exp so a user wide revocation does not
+	// hit tokens issued within the same second right after it
+	jwt.TimePrecision = time.Millisecond
+}
+
+// Claims are the claims carried by access token JWTs
+type Claims struct {
+	Role            string           `json:"role"`
+	RoleID          uint             `json:"role_id"`
+	EmailVerifiedAt *jwt.NumericDate `json:"email_verified_at,omitempty"`
+	SessionID       string           `json:"sid,omitempty"`
+	Actor           *ActorClaim      `json:"act,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// ActorClaim identifies the admin acting as the subject of an impersonation token (RFC 8693)
+type ActorClaim struct {
+	Subject string `json:"sub"`
+}
+
+// JWTStrategy issues stateless signed tokens. Only the revocation denylist is
+// consulted on validation, the user is rebuilt from the claims.
+type JWTStrategy struct {
+	method      jwt.SigningMethod
+	signKey     crypto.PrivateKey
+	verifyKey   crypto.PublicKey
+	issuer      string
+	maxLifetime time.Duration
+	revokedRepo *repository.RevokedTokenRepository
+}
+
+func NewJWTStrategy(db *gorm.DB, cfg *config.Config) (*JWTStrategy, error) {
+	s := &JWTStrategy{
+		issuer:      cfg.JWTIssuer,
+		maxLifetime: cfg.JWTExpiry,
+		revokedRepo: repository.NewRevokedTokenRepository(db),
+	}
+
+	switch cfg.JWTAlgorithm {
+	case AlgorithmHS256, "":
+		s.method = jwt.SigningMethodHS256
+		s.signKey = []byte(cfg.JWTSecret)
+		s.verifyKey = []byte(cfg.JWTSecret)
+	case AlgorithmEdDSA:
+		privateKey, err := loadEd25519PrivateKey(cfg.JWTPrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		s.method = jwt.SigningMethodEdDSA
+		s.signKey = privateKey
+		s.verifyKey = privateKey.Public()
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm: %s", cfg.JWTAlgorithm)
+	}
+
+	return s, nil
+}
+
+// loadEd25519PrivateKey reads a PKCS#8 PEM encoded Ed25519 private key
+func loadEd25519PrivateKey(path string) (ed25519.PrivateKey, error) {
+	if path == "" {
+		return nil, errors.New("security.jwt_private_key_file is required for EdDSA")
+	}
+
+	// #nosec G304 -- path comes from the application configuration
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWT private key: %w", err)
+	}
+
+	key, err := jwt.ParseEdPrivateKeyFromPEM(pemBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JWT private key: %w", err)
+	}
+
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("JWT private key is not an Ed25519 key")
+	}
+
+	return privateKey, nil
+}
+
+func (s *JWTStrategy) Issue(ctx context.Context, user *model.User, sessionID string, expiresIn time.Duration) (*model.AccessToken, error) {
+	return s.issue(user, sessionID, nil, expiresIn)
+}
+
+func (s *JWTStrategy) IssueImpersonation(ctx context.Context, user *model.User, impersonatorID uint, expiresIn time.Duration) (*model.AccessToken, error) {
+	return s.issue(user, "", &impersonatorID, expiresIn)
+}
+
+// issue signs a token for the user, impersonatorID is set when an admin acts as the user
+func (s *JWTStrategy) issue(user *model.User, sessionID string, impersonatorID *uint, expiresIn time.Duration) (*model.AccessToken, error) {
+	// Denylist entries for sessions and users only live for maxLifetime
+	if expiresIn > s.maxLifetime {
+		expiresIn = s.maxLifetime
+	}
+
+	jti, err := securetoken.Generate(16)
+	if err != nil {
+		return nil, err
+	}
+
+	now := timezone.Now()
+	expiresAt := now.Add(expiresIn)
+
+	claims := Claims{
+		Role:      user.Role.Code,
+		RoleID:    user.RoleID,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Issuer:    s.issuer,
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	if user.EmailVerifiedAt != nil {
+		claims.EmailVerifiedAt = jwt.NewNumericDate(*user.EmailVerifiedAt)
+	}
+	if impersonatorID != nil {
+		claims.Actor = &ActorClaim{Subject: strconv.FormatUint(uint64(*impersonatorID), 10)}
+	}
+
+	signed, err := jwt.NewWithClaims(s.method, claims).SignedString(s.signKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AccessToken{
+		Token:          signed,
+		UserID:         user.ID,
+		SessionID:      sessionID,
+		ExpiresAt:      expiresAt,
+		ImpersonatorID: impersonatorID,
+		User:           *user,
+	}, nil
+}
+
+// parse verifies the signature and the registered claims of a token
+func (s *JWTStrategy) parse(token string) (*Claims, uint, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return s.verifyKey, nil
+	},
+		jwt.WithValidMethods([]string{s.method.Alg()}),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return nil, 0, ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil || claims.ID == "" || claims.IssuedAt == nil {
+		return nil, 0, ErrInvalidToken
+	}
+
+	return claims, uint(userID), nil
+}
+
+func (s *JWTStrategy) Validate(ctx context.Context, token string) (*model.AccessToken, error) {
+	claims, userID, err := s.parse(token)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.revokedRepo.IsRevoked(ctx, userID, claims.ID, claims.SessionID, timezone.ToLocal(claims.IssuedAt.Time))
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrInvalidToken
+	}
+
+	user := model.User{
+		RoleID: claims.RoleID,
+		Role:   model.Role{Code: claims.Role},
+	}
+	user.ID = userID
+	user.Role.ID = claims.RoleID
+	if claims.EmailVerifiedAt != nil {
+		verifiedAt := timezone.ToLocal(claims.EmailVerifiedAt.Time)
+		user.EmailVerifiedAt = &verifiedAt
+	}
+
+	accessToken := &model.AccessToken{
+		Token:     token,
+		UserID:    userID,
+		SessionID: claims.SessionID,
+		ExpiresAt: timezone.ToLocal(claims.ExpiresAt.Time),
+		User:      user,
+	}
+	accessToken.CreatedAt = timezone.ToLocal(claims.IssuedAt.Time)
+	if claims.Actor != nil {
+		impersonatorID, err := strconv.ParseUint(claims.Actor.Subject, 10, 32)
+		if err != nil {
+			return nil, ErrInvalidToken
+		}
+		actorID := uint(impersonatorID)
+		accessToken.ImpersonatorID = &actorID
+	}
+
+	return accessToken, nil
+}
+
+func (s *JWTStrategy) Revoke(ctx context.Context, accessToken *model.AccessToken) error {
+	claims, userID, err := s.parse(accessToken.Token)
+	if err != nil {
+		return err
+	}
+
+	return s.revokedRepo.RevokeJTI(ctx, userID, claims.ID, timezone.ToLocal(claims.ExpiresAt.Time))
+}
+
+func (s *JWTStrategy) RevokeSession(ctx context.Context, userID uint, sessionID string) error {
+	if sessionID == "" {
+		return nil
+	}
+	return s.revokedRepo.RevokeSession(ctx, userID, sessionID, timezone.Now().Add(s.maxLifetime))
+}
+
+func (s *JWTStrategy) RevokeAllUserTokens(ctx context.Context, userID uint) error {
+	return s.revokedRepo.RevokeUser(ctx, userID, timezone.Now().Add(s.maxLifetime))
+}
+
+func (s *JWTStrategy) WithDB(db *gorm.DB) Strategy {
+	clone := *s
+	clone.revokedRepo = repository.NewRevokedTokenRepository(db)
+	return &clone
+}
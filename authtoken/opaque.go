@@ -0,0 +1,61 @@
+package authtoken
+
+import (
+	"context"
+	"go-api/model"
+	"go-api/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OpaqueStrategy stores random tokens in the database and looks them up on every request.
+// Tokens that were not used within the idle timeout are rejected.
+type OpaqueStrategy struct {
+	accessTokenRepo *repository.AccessTokenRepository
+	idleTimeout     time.Duration
+}
+
+func NewOpaqueStrategy(db *gorm.DB, idleTimeout time.Duration) *OpaqueStrategy {
+	return &OpaqueStrategy{
+		accessTokenRepo: repository.NewAccessTokenRepository(db),
+		idleTimeout:     idleTimeout,
+	}
+}
+
+func (s *OpaqueStrategy) Issue(ctx context.Context, user *model.User, sessionID string, expiresIn time.Duration) (*model.AccessToken, error) {
+	return s.accessTokenRepo.CreateForSession(ctx, user.ID, sessionID, expiresIn)
+}
+
+func (s *OpaqueStrategy) IssueImpersonation(ctx context.Context, user *model.User, impersonatorID uint, expiresIn time.Duration) (*model.AccessToken, error) {
+	return s.accessTokenRepo.CreateImpersonation(ctx, user.ID, impersonatorID, expiresIn)
+}
+
+func (s *OpaqueStrategy) Validate(ctx context.Context, token string) (*model.AccessToken, error) {
+	accessToken, err := s.accessTokenRepo.FindByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !accessToken.IsValid() || accessToken.IsIdle(s.idleTimeout) {
+		return nil, ErrInvalidToken
+	}
+
+	return accessToken, nil
+}
+
+func (s *OpaqueStrategy) Revoke(ctx context.Context, accessToken *model.AccessToken) error {
+	return s.accessTokenRepo.RevokeToken(ctx, accessToken.Token)
+}
+
+func (s *OpaqueStrategy) RevokeSession(ctx context.Context, userID uint, sessionID string) error {
+	return s.accessTokenRepo.RevokeSession(ctx, sessionID)
+}
+
+func (s *OpaqueStrategy) RevokeAllUserTokens(ctx context.Context, userID uint) error {
+	return s.accessTokenRepo.RevokeAllUserTokens(ctx, userID)
+}
+
+func (s *OpaqueStrategy) WithDB(db *gorm.DB) Strategy {
+	return NewOpaqueStrategy(db, s.idleTimeout)
+}
@@ -0,0 +1,51 @@
+package authtoken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api/config"
+	"go-api/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// StrategyOpaque stores random tokens in the access_tokens table
+	StrategyOpaque = "opaque"
+	// StrategyJWT issues signed, stateless JWTs checked against a revocation denylist
+	StrategyJWT = "jwt"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Strategy issues, validates and revokes access tokens
+type Strategy interface {
+	// Issue creates an access token for the user, linked to a session (refresh token family)
+	Issue(ctx context.Context, user *model.User, sessionID string, expiresIn time.Duration) (*model.AccessToken, error)
+	// IssueImpersonation creates a token for the user that is marked with the admin acting as them
+	IssueImpersonation(ctx context.Context, user *model.User, impersonatorID uint, expiresIn time.Duration) (*model.AccessToken, error)
+	// Validate returns the access token with its user if the token is valid and not revoked
+	Validate(ctx context.Context, token string) (*model.AccessToken, error)
+	// Revoke invalidates a single access token
+	Revoke(ctx context.Context, accessToken *model.AccessToken) error
+	// RevokeSession invalidates every access token issued for a session
+	RevokeSession(ctx context.Context, userID uint, sessionID string) error
+	// RevokeAllUserTokens invalidates every access token of a user
+	RevokeAllUserTokens(ctx context.Context, userID uint) error
+	// WithDB returns a copy of the strategy using db, so it can take part in a transaction
+	WithDB(db *gorm.DB) Strategy
+}
+
+// NewStrategy creates the token strategy selected in the configuration
+func NewStrategy(db *gorm.DB, cfg *config.Config) (Strategy, error) {
+	switch cfg.TokenStrategy {
+	case StrategyOpaque, "":
+		return NewOpaqueStrategy(db, cfg.TokenIdleTimeout), nil
+	case StrategyJWT:
+		return NewJWTStrategy(db, cfg)
+	default:
+		return nil, fmt.Errorf("unknown token strategy: %s", cfg.TokenStrategy)
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"go-api/app"
+	"go-api/config"
+	authService "go-api/domain/auth/service"
+	"go-api/shared/logger"
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+// purgeCmd represents the purge command
+var purgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Purge accounts whose deletion grace period has ended",
+	Long: `Permanently delete every account that was deleted by its owner and whose
+grace period (auth.account_deletion_grace_period) has ended.
+
+All data linked to a purged account is removed with it. Run this command
+periodically, for example once a day from cron.
+
+Examples:
+  purge`,
+	Run: func(cmd *cobra.Command, args []string) {
+		config.InitConfig()
+		purgeAccounts()
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(purgeCmd)
+}
+
+func purgeAccounts() {
+	if err := logger.Init(); err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+
+	provider, err := app.BootProvider(config.Get())
+	if err != nil {
+		log.Fatalf("Failed to initialize application services: %v", err)
+	}
+	defer provider.ShutdownProvider()
+
+	purged, err := authService.NewAuthService(provider).PurgeDeletedAccounts(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to purge accounts after %d purged: %v", purged, err)
+	}
+
+	log.Printf("Purged %d account(s)", purged)
+}
@@ -16,11 +16,13 @@ This CLI provides commands for:
 - Starting the HTTP API server (serve)
 - Managing database migrations (migrate)
 - Running database seeders (seed)
+- Purging accounts scheduled for deletion (purge)
 
 Examples:
   serve                     # Start the server
   migrate up                # Run migrations
   seed create posts         # Create seeder
+  purge                     # Purge deleted accounts
 
 Use the available subcommands to manage your API application.`,
 }
@@ -29,6 +29,17 @@ type Config struct {
 	// Security configurations
 	SecurityHeadersEnabled bool
 	TrustedProxies         string
+	// Access token configurations
+	TokenStrategy     string
+	JWTAlgorithm      string
+	JWTPrivateKeyFile string
+	JWTIssuer         string
+	// Session lifetime configurations, zero disables the limit
+	TokenIdleTimeout      time.Duration
+	TokenAbsoluteLifetime time.Duration
+	TokenTouchInterval    time.Duration
+	// How long the permissions of a role are cached
+	PermissionCacheTTL time.Duration
 	// Database configurations
 	DBMaxIdleConns int
 	DBMaxOpenConns int
@@ -50,6 +61,62 @@ type Config struct {
 	MailPassword string
 	FromName     string
 	FromEmail    string
+	// Auth flow configurations
+	PasswordResetExpiry             time.Duration
+	EmailVerificationExpiry         time.Duration
+	EmailVerificationResendInterval time.Duration
+	RefreshTokenExpiry              time.Duration
+	TwoFactorIssuer                 string
+	TwoFactorChallengeExpiry        time.Duration
+	LockoutThreshold                int
+	LockoutDuration                 time.Duration
+	LockoutMaxDuration              time.Duration
+	EmailChangeExpiry               time.Duration
+	MagicLinkExpiry                 time.Duration
+	ImpersonationExpiry             time.Duration
+	AccountDeletionGracePeriod      time.Duration
+	// Password hashing configurations
+	PasswordHashAlgorithm string
+	BcryptCost            int
+	Argon2Memory          uint32 // KiB
+	Argon2Iterations      uint32
+	Argon2Parallelism     uint8
+	Argon2SaltLength      uint32
+	Argon2KeyLength       uint32
+	// Password policy configurations
+	PasswordMinLength            int
+	PasswordMaxLength            int
+	PasswordRequireLetter        bool
+	PasswordRequireUppercase     bool
+	PasswordRequireLowercase     bool
+	PasswordRequireDigit         bool
+	PasswordRequireSymbol        bool
+	PasswordMaxRepeatedChars     int
+	PasswordDisallowPersonalInfo bool
+	PasswordDenylistFile         string
+	PasswordHistorySize          int
+	// OpenID Connect login configurations
+	OIDCProviders   map[string]OIDCProviderConfig
+	OIDCStateExpiry time.Duration
+	// OAuth authorization server configurations
+	OAuthCodeExpiry         time.Duration
+	OAuthAccessTokenExpiry  time.Duration
+	OAuthRefreshTokenExpiry time.Duration
+	// WebAuthn passkey configurations
+	WebAuthnRPID            string
+	WebAuthnRPDisplayName   string
+	WebAuthnRPOrigins       []string
+	WebAuthnChallengeExpiry time.Duration
+}
+
+// OIDCProviderConfig configures a single OpenID Connect login provider
+type OIDCProviderConfig struct {
+	DisplayName  string   `mapstructure:"display_name"`
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
 }
 
 var GlobalConfig *Config
@@ -96,9 +163,17 @@ func setDefaults() {
 	// Security defaults - NO HARDCODED SECRETS
 	// These MUST be set in config.yaml or environment variables
 	viper.SetDefault("security.jwt_secret", "")
-	viper.SetDefault("security.jwt_expiry", 24*time.Hour)
+	viper.SetDefault("security.jwt_expiry", 15*time.Minute) // Short-lived, renewed with refresh tokens
 	viper.SetDefault("security.headers_enabled", true)
 	viper.SetDefault("security.trusted_proxies", "")
+	viper.SetDefault("security.token_strategy", "opaque")
+	viper.SetDefault("security.jwt_algorithm", "HS256")
+	viper.SetDefault("security.jwt_private_key_file", "")
+	viper.SetDefault("security.jwt_issuer", "go-api")
+	viper.SetDefault("security.token_idle_timeout", 0)
+	viper.SetDefault("security.token_absolute_lifetime", 0)
+	viper.SetDefault("security.token_touch_interval", time.Minute)
+	viper.SetDefault("security.permission_cache_ttl", time.Minute)
 
 	// App defaults - Secure defaults for production
 	viper.SetDefault("app.port", "8000")
@@ -112,7 +187,7 @@ func setDefaults() {
 	// CORS defaults
 	viper.SetDefault("cors.allowed_origins", "http://localhost:3000")
 	viper.SetDefault("cors.allowed_methods", "GET,POST,PUT,DELETE,OPTIONS")
-	viper.SetDefault("cors.allowed_headers", "Origin,Content-Type,Accept,Authorization")
+	viper.SetDefault("cors.allowed_headers", "Origin,Content-Type,Accept,Authorization,X-API-Key")
 
 	// Rate limiting defaults
 	viper.SetDefault("rate_limit.enabled", true)
@@ -131,6 +206,55 @@ func setDefaults() {
 	viper.SetDefault("mail.password", "")
 	viper.SetDefault("mail.from_name", "Go API App")
 	viper.SetDefault("mail.from_email", "")
+
+	// Auth flow defaults
+	viper.SetDefault("auth.password_reset_expiry", time.Hour)
+	viper.SetDefault("auth.email_verification_expiry", 24*time.Hour)
+	viper.SetDefault("auth.email_verification_resend_interval", time.Minute)
+	viper.SetDefault("auth.refresh_token_expiry", 30*24*time.Hour)
+	viper.SetDefault("auth.two_factor_issuer", "Go API App")
+	viper.SetDefault("auth.two_factor_challenge_expiry", 5*time.Minute)
+	viper.SetDefault("auth.lockout_threshold", 5)
+	viper.SetDefault("auth.lockout_duration", time.Minute)
+	viper.SetDefault("auth.lockout_max_duration", 24*time.Hour)
+	viper.SetDefault("auth.email_change_expiry", time.Hour)
+	viper.SetDefault("auth.magic_link_expiry", 15*time.Minute)
+	viper.SetDefault("auth.impersonation_expiry", 15*time.Minute)
+	viper.SetDefault("auth.account_deletion_grace_period", 30*24*time.Hour)
+
+	// Password hashing defaults
+	viper.SetDefault("password.hash_algorithm", "argon2id")
+	viper.SetDefault("password.bcrypt_cost", 12)
+	viper.SetDefault("password.argon2_memory", 64*1024)
+	viper.SetDefault("password.argon2_iterations", 3)
+	viper.SetDefault("password.argon2_parallelism", 2)
+	viper.SetDefault("password.argon2_salt_length", 16)
+	viper.SetDefault("password.argon2_key_length", 32)
+
+	// Password policy defaults
+	viper.SetDefault("password.min_length", 8)
+	viper.SetDefault("password.max_length", 100)
+	viper.SetDefault("password.require_letter", true)
+	viper.SetDefault("password.require_uppercase", false)
+	viper.SetDefault("password.require_lowercase", false)
+	viper.SetDefault("password.require_digit", true)
+	viper.SetDefault("password.require_symbol", false)
+	viper.SetDefault("password.max_repeated_chars", 0)
+	viper.SetDefault("password.disallow_personal_info", true)
+	viper.SetDefault("password.denylist_file", "")
+	viper.SetDefault("password.history_size", 0)
+
+	// OpenID Connect defaults
+	viper.SetDefault("oidc.state_expiry", 10*time.Minute)
+
+	// OAuth authorization server defaults
+	viper.SetDefault("oauth.authorization_code_expiry", 10*time.Minute)
+	viper.SetDefault("oauth.access_token_expiry", time.Hour)
+	viper.SetDefault("oauth.refresh_token_expiry", 30*24*time.Hour)
+
+	// WebAuthn passkey defaults
+	viper.SetDefault("webauthn.rp_display_name", "Go API App")
+	viper.SetDefault("webauthn.challenge_expiry", 5*time.Minute)
 }
 
 func buildConfig() {
@@ -143,6 +267,14 @@ func buildConfig() {
 		JWTExpiry:              viper.GetDuration("security.jwt_expiry"),
 		SecurityHeadersEnabled: viper.GetBool("security.headers_enabled"),
 		TrustedProxies:         viper.GetString("security.trusted_proxies"),
+		TokenStrategy:          viper.GetString("security.token_strategy"),
+		JWTAlgorithm:           viper.GetString("security.jwt_algorithm"),
+		JWTPrivateKeyFile:      viper.GetString("security.jwt_private_key_file"),
+		JWTIssuer:              viper.GetString("security.jwt_issuer"),
+		TokenIdleTimeout:       viper.GetDuration("security.token_idle_timeout"),
+		TokenAbsoluteLifetime:  viper.GetDuration("security.token_absolute_lifetime"),
+		TokenTouchInterval:     viper.GetDuration("security.token_touch_interval"),
+		PermissionCacheTTL:     viper.GetDuration("security.permission_cache_ttl"),
 
 		// App configurations
 		AppPort:         viper.GetString("app.port"),
@@ -180,6 +312,62 @@ func buildConfig() {
 		MailPassword: viper.GetString("mail.password"),
 		FromName:     viper.GetString("mail.from_name"),
 		FromEmail:    viper.GetString("mail.from_email"),
+
+		// Auth flow configurations
+		PasswordResetExpiry:             viper.GetDuration("auth.password_reset_expiry"),
+		EmailVerificationExpiry:         viper.GetDuration("auth.email_verification_expiry"),
+		EmailVerificationResendInterval: viper.GetDuration("auth.email_verification_resend_interval"),
+		RefreshTokenExpiry:              viper.GetDuration("auth.refresh_token_expiry"),
+		TwoFactorIssuer:                 viper.GetString("auth.two_factor_issuer"),
+		TwoFactorChallengeExpiry:        viper.GetDuration("auth.two_factor_challenge_expiry"),
+		LockoutThreshold:                viper.GetInt("auth.lockout_threshold"),
+		LockoutDuration:                 viper.GetDuration("auth.lockout_duration"),
+		LockoutMaxDuration:              viper.GetDuration("auth.lockout_max_duration"),
+		EmailChangeExpiry:               viper.GetDuration("auth.email_change_expiry"),
+		MagicLinkExpiry:                 viper.GetDuration("auth.magic_link_expiry"),
+		ImpersonationExpiry:             viper.GetDuration("auth.impersonation_expiry"),
+		AccountDeletionGracePeriod:      viper.GetDuration("auth.account_deletion_grace_period"),
+
+		// Password hashing configurations
+		PasswordHashAlgorithm: viper.GetString("password.hash_algorithm"),
+		BcryptCost:            viper.GetInt("password.bcrypt_cost"),
+		Argon2Memory:          viper.GetUint32("password.argon2_memory"),
+		Argon2Iterations:      viper.GetUint32("password.argon2_iterations"),
+		Argon2Parallelism:     uint8(viper.GetUint("password.argon2_parallelism")),
+		Argon2SaltLength:      viper.GetUint32("password.argon2_salt_length"),
+		Argon2KeyLength:       viper.GetUint32("password.argon2_key_length"),
+
+		// Password policy configurations
+		PasswordMinLength:            viper.GetInt("password.min_length"),
+		PasswordMaxLength:            viper.GetInt("password.max_length"),
+		PasswordRequireLetter:        viper.GetBool("password.require_letter"),
+		PasswordRequireUppercase:     viper.GetBool("password.require_uppercase"),
+		PasswordRequireLowercase:     viper.GetBool("password.require_lowercase"),
+		PasswordRequireDigit:         viper.GetBool("password.require_digit"),
+		PasswordRequireSymbol:        viper.GetBool("password.require_symbol"),
+		PasswordMaxRepeatedChars:     viper.GetInt("password.max_repeated_chars"),
+		PasswordDisallowPersonalInfo: viper.GetBool("password.disallow_personal_info"),
+		PasswordDenylistFile:         viper.GetString("password.denylist_file"),
+		PasswordHistorySize:          viper.GetInt("password.history_size"),
+
+		// OpenID Connect login configurations
+		OIDCStateExpiry: viper.GetDuration("oidc.state_expiry"),
+
+		// OAuth authorization server configurations
+		OAuthCodeExpiry:         viper.GetDuration("oauth.authorization_code_expiry"),
+		OAuthAccessTokenExpiry:  viper.GetDuration("oauth.access_token_expiry"),
+		OAuthRefreshTokenExpiry: viper.GetDuration("oauth.refresh_token_expiry"),
+
+		// WebAuthn passkey configurations
+		WebAuthnRPID:            viper.GetString("webauthn.rp_id"),
+		WebAuthnRPDisplayName:   viper.GetString("webauthn.rp_display_name"),
+		WebAuthnRPOrigins:       viper.GetStringSlice("webauthn.rp_origins"),
+		WebAuthnChallengeExpiry: viper.GetDuration("webauthn.challenge_expiry"),
+	}
+
+	// Load OpenID Connect providers, keyed by the name used in the login URL
+	if err := viper.UnmarshalKey("oidc.providers", &GlobalConfig.OIDCProviders); err != nil {
+		log.Fatalf("Invalid oidc.providers configuration: %v", err)
 	}
 
 	// Load timezone location
@@ -218,6 +406,78 @@ func validateConfig() {
 		log.Fatalf("JWT secret must be at least 32 characters long for security")
 	}
 
+	// Validate access token strategy
+	switch GlobalConfig.TokenStrategy {
+	case "opaque", "jwt":
+	default:
+		log.Fatalf("security.token_strategy must be either 'opaque' or 'jwt', got '%s'", GlobalConfig.TokenStrategy)
+	}
+	if GlobalConfig.TokenStrategy == "jwt" {
+		switch GlobalConfig.JWTAlgorithm {
+		case "HS256":
+		case "EdDSA":
+			if GlobalConfig.JWTPrivateKeyFile == "" {
+				log.Fatalf("security.jwt_private_key_file is required when using the EdDSA algorithm")
+			}
+		default:
+			log.Fatalf("security.jwt_algorithm must be either 'HS256' or 'EdDSA', got '%s'", GlobalConfig.JWTAlgorithm)
+		}
+	}
+
+	// Validate session lifetimes
+	if GlobalConfig.TokenIdleTimeout < 0 || GlobalConfig.TokenAbsoluteLifetime < 0 {
+		log.Fatalf("security.token_idle_timeout and security.token_absolute_lifetime cannot be negative")
+	}
+	if GlobalConfig.TokenTouchInterval <= 0 {
+		log.Fatalf("security.token_touch_interval must be greater than zero")
+	}
+	// Last use is only written once per touch interval, a shorter idle timeout would end active sessions
+	if GlobalConfig.TokenIdleTimeout > 0 && GlobalConfig.TokenIdleTimeout <= GlobalConfig.TokenTouchInterval {
+		log.Fatalf("security.token_idle_timeout must be longer than security.token_touch_interval")
+	}
+	if GlobalConfig.PermissionCacheTTL < 0 {
+		log.Fatalf("security.permission_cache_ttl cannot be negative")
+	}
+
+	// Validate password hashing parameters
+	switch GlobalConfig.PasswordHashAlgorithm {
+	case "argon2id", "bcrypt":
+	default:
+		log.Fatalf("password.hash_algorithm must be either 'argon2id' or 'bcrypt', got '%s'", GlobalConfig.PasswordHashAlgorithm)
+	}
+	if GlobalConfig.BcryptCost < 10 || GlobalConfig.BcryptCost > 31 {
+		log.Fatalf("password.bcrypt_cost must be between 10 and 31")
+	}
+	if viper.GetUint("password.argon2_parallelism") < 1 || viper.GetUint("password.argon2_parallelism") > 255 {
+		log.Fatalf("password.argon2_parallelism must be between 1 and 255")
+	}
+	if GlobalConfig.Argon2Memory < 8*uint32(GlobalConfig.Argon2Parallelism) || GlobalConfig.Argon2Iterations < 1 {
+		log.Fatalf("password.argon2_memory must be at least 8 KiB per thread and password.argon2_iterations at least 1")
+	}
+	if GlobalConfig.Argon2SaltLength < 16 || GlobalConfig.Argon2KeyLength < 16 {
+		log.Fatalf("password.argon2_salt_length and password.argon2_key_length must be at least 16 bytes")
+	}
+
+	// Validate password policy
+	if GlobalConfig.PasswordMinLength < 1 || GlobalConfig.PasswordMaxLength < GlobalConfig.PasswordMinLength {
+		log.Fatalf("password.min_length must be at least 1 and password.max_length at least password.min_length")
+	}
+	if GlobalConfig.PasswordMaxRepeatedChars < 0 || GlobalConfig.PasswordHistorySize < 0 {
+		log.Fatalf("password.max_repeated_chars and password.history_size cannot be negative")
+	}
+
+	// Validate OpenID Connect providers
+	for name, provider := range GlobalConfig.OIDCProviders {
+		if provider.Issuer == "" || provider.ClientID == "" || provider.RedirectURL == "" {
+			log.Fatalf("oidc.providers.%s requires issuer, client_id and redirect_url", name)
+		}
+	}
+
+	// Passkeys are enabled by setting the relying party ID
+	if GlobalConfig.WebAuthnRPID != "" && len(GlobalConfig.WebAuthnRPOrigins) == 0 {
+		log.Fatalf("webauthn.rp_origins is required when webauthn.rp_id is set")
+	}
+
 	// Validate database URL format and SSL requirements
 	if !strings.Contains(GlobalConfig.DatabaseURL, "sslmode") {
 		log.Printf("Warning: Database connection should specify SSL mode for production")
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"go-api/config"
+	"go-api/tenant"
 	"log"
 	"time"
 
@@ -66,6 +67,11 @@ func initDatabaseConnection(cfg *config.Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Scope organization-owned models to the organization of the request
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		return nil, fmt.Errorf("failed to register tenant plugin: %w", err)
+	}
+
 	// Configure connection pool to prevent memory leaks and optimize performance
 	sqlDB, err := db.DB()
 	if err != nil {
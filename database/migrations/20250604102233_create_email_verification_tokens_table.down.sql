@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_email_verification_tokens_deleted_at;
+DROP INDEX IF EXISTS idx_email_verification_tokens_user_id;
+DROP TABLE IF EXISTS email_verification_tokens;
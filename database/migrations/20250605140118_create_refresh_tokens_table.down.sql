@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_deleted_at;
+DROP INDEX IF EXISTS idx_refresh_tokens_access_token_id;
+DROP INDEX IF EXISTS idx_refresh_tokens_user_id;
+DROP INDEX IF EXISTS idx_refresh_tokens_family_id;
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_access_tokens_session_id;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS session_id;
@@ -0,0 +1,11 @@
+-- Link access tokens to the refresh token family (session) they were issued for
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'access_tokens' AND column_name = 'session_id') THEN
+        ALTER TABLE access_tokens ADD COLUMN session_id VARCHAR(64) NOT NULL DEFAULT '';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_access_tokens_session_id') THEN
+        CREATE INDEX idx_access_tokens_session_id ON access_tokens(session_id);
+    END IF;
+END $$;
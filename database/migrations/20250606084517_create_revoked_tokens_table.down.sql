@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_revoked_tokens_deleted_at;
+DROP INDEX IF EXISTS idx_revoked_tokens_expires_at;
+DROP INDEX IF EXISTS idx_revoked_tokens_user_id;
+DROP INDEX IF EXISTS idx_revoked_tokens_session_id;
+DROP TABLE IF EXISTS revoked_tokens;
@@ -0,0 +1,6 @@
+-- Raw tokens cannot be recovered from their digests, so all sessions are invalidated
+DELETE FROM access_tokens;
+DROP INDEX IF EXISTS idx_access_tokens_token_hash;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS token_prefix;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS token_hash;
+ALTER TABLE access_tokens ADD COLUMN IF NOT EXISTS token VARCHAR(255) UNIQUE NOT NULL;
@@ -0,0 +1,27 @@
+-- Store only a SHA-256 digest of access tokens plus a short display prefix.
+-- Existing tokens are converted in place so current sessions keep working.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'access_tokens' AND column_name = 'token_hash') THEN
+        ALTER TABLE access_tokens ADD COLUMN token_hash VARCHAR(64) NULL;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'access_tokens' AND column_name = 'token_prefix') THEN
+        ALTER TABLE access_tokens ADD COLUMN token_prefix VARCHAR(16) NOT NULL DEFAULT '';
+    END IF;
+
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'access_tokens' AND column_name = 'token') THEN
+        UPDATE access_tokens
+        SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex'),
+            token_prefix = left(token, 8)
+        WHERE token_hash IS NULL;
+
+        ALTER TABLE access_tokens DROP COLUMN token;
+    END IF;
+
+    ALTER TABLE access_tokens ALTER COLUMN token_hash SET NOT NULL;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_access_tokens_token_hash') THEN
+        CREATE UNIQUE INDEX idx_access_tokens_token_hash ON access_tokens(token_hash);
+    END IF;
+END $$;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_access_tokens_user_id;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS last_used_at;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS device_label;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS ip_address;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS user_agent;
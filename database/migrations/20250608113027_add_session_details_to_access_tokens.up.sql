@@ -0,0 +1,23 @@
+-- Track where and when each access token (session) is used
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'access_tokens' AND column_name = 'user_agent') THEN
+        ALTER TABLE access_tokens ADD COLUMN user_agent VARCHAR(512) NOT NULL DEFAULT '';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'access_tokens' AND column_name = 'ip_address') THEN
+        ALTER TABLE access_tokens ADD COLUMN ip_address VARCHAR(45) NOT NULL DEFAULT '';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'access_tokens' AND column_name = 'device_label') THEN
+        ALTER TABLE access_tokens ADD COLUMN device_label VARCHAR(100) NOT NULL DEFAULT '';
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'access_tokens' AND column_name = 'last_used_at') THEN
+        ALTER TABLE access_tokens ADD COLUMN last_used_at TIMESTAMP NULL;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_access_tokens_user_id') THEN
+        CREATE INDEX idx_access_tokens_user_id ON access_tokens(user_id);
+    END IF;
+END $$;
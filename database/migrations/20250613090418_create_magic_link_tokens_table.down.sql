@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_magic_link_tokens_deleted_at;
+DROP INDEX IF EXISTS idx_magic_link_tokens_user_id;
+DROP TABLE IF EXISTS magic_link_tokens;
@@ -0,0 +1,12 @@
+CREATE TABLE password_histories (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_password_histories_user_id ON password_histories(user_id, created_at);
+CREATE INDEX idx_password_histories_deleted_at ON password_histories(deleted_at);
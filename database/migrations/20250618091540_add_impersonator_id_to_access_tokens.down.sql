@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_access_tokens_impersonator_id;
+ALTER TABLE access_tokens DROP COLUMN IF EXISTS impersonator_id;
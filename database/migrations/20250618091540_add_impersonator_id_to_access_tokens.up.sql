@@ -0,0 +1,11 @@
+-- Mark access tokens issued to an admin impersonating the user
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'access_tokens' AND column_name = 'impersonator_id') THEN
+        ALTER TABLE access_tokens ADD COLUMN impersonator_id INTEGER NULL REFERENCES users(id) ON DELETE CASCADE;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_access_tokens_impersonator_id') THEN
+        CREATE INDEX idx_access_tokens_impersonator_id ON access_tokens(impersonator_id);
+    END IF;
+END $$;
@@ -0,0 +1,12 @@
+-- Remember when the session (refresh token family) started so its total age can be capped
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'refresh_tokens' AND column_name = 'session_started_at') THEN
+        ALTER TABLE refresh_tokens ADD COLUMN session_started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+        -- Existing families started with their oldest token
+        UPDATE refresh_tokens r SET session_started_at = f.started_at
+        FROM (SELECT family_id, MIN(created_at) AS started_at FROM refresh_tokens GROUP BY family_id) f
+        WHERE r.family_id = f.family_id AND f.started_at IS NOT NULL;
+    END IF;
+END $$;
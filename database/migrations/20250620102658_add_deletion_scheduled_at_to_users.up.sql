@@ -0,0 +1,11 @@
+-- Accounts deleted by their owner are purged once the grace period is over
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'users' AND column_name = 'deletion_scheduled_at') THEN
+        ALTER TABLE users ADD COLUMN deletion_scheduled_at TIMESTAMP NULL;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_indexes WHERE indexname = 'idx_users_deletion_scheduled_at') THEN
+        CREATE INDEX idx_users_deletion_scheduled_at ON users(deletion_scheduled_at) WHERE deletion_scheduled_at IS NOT NULL;
+    END IF;
+END $$;
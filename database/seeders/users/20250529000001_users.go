@@ -5,10 +5,10 @@ import (
 	"go-api/config"
 	"go-api/database"
 	"go-api/model"
+	"go-api/passwordhash"
 	"log"
 	"math/big"
 
-	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
@@ -44,12 +44,17 @@ func runUsers(db *gorm.DB) error {
 		return err
 	}
 
-	adminHashedPassword, err := bcrypt.GenerateFromPassword([]byte(adminPassword), bcrypt.DefaultCost)
+	passwords, err := passwordhash.NewHasher(config.Get())
+	if err != nil {
+		return err
+	}
+
+	adminHashedPassword, err := passwords.Hash(adminPassword)
 	if err != nil {
 		log.Fatal("Failed to hash admin password:", err)
 	}
 
-	userHashedPassword, err := bcrypt.GenerateFromPassword([]byte(userPassword), bcrypt.DefaultCost)
+	userHashedPassword, err := passwords.Hash(userPassword)
 	if err != nil {
 		log.Fatal("Failed to hash user password:", err)
 	}
@@ -59,13 +64,13 @@ func runUsers(db *gorm.DB) error {
 		{
 			Name:     "Admin User",
 			Email:    "admin@example.com",
-			Password: string(adminHashedPassword),
+			Password: adminHashedPassword,
 			RoleID:   1, // Assuming admin role
 		},
 		{
 			Name:     "Regular User",
 			Email:    "user@example.com",
-			Password: string(userHashedPassword),
+			Password: userHashedPassword,
 			RoleID:   2, // Assuming user role
 		},
 	}
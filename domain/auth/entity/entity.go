@@ -1,5 +1,11 @@
 package entity
 
+import (
+	"encoding/json"
+	"go-api/model"
+	"time"
+)
+
 // LoginRequest represents the login request payload
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
@@ -10,5 +16,229 @@ type LoginRequest struct {
 type RegisterRequest struct {
 	Name     string `json:"name" validate:"required,min=2,max=100"`
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=8,max=100"`
+	Password string `json:"password" validate:"required"`
+}
+
+// ForgotPasswordRequest represents the forgot password request payload
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest represents the password reset request payload
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// VerifyEmailRequest represents the email verification request payload
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// RefreshTokenRequest represents the token refresh request payload
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// TokenPair represents the tokens issued on login and on refresh
+type TokenPair struct {
+	AccessToken      *model.AccessToken
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// SessionResponse represents an active session (device) of the current user
+type SessionResponse struct {
+	ID          uint       `json:"id"`
+	DeviceLabel string     `json:"device_label"`
+	UserAgent   string     `json:"user_agent"`
+	IPAddress   string     `json:"ip_address"`
+	TokenPrefix string     `json:"token_prefix"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	Current     bool       `json:"current"`
+}
+
+// LoginResult represents the outcome of a password login. When two-factor
+// authentication is enabled only the challenge fields are set.
+type LoginResult struct {
+	Tokens             *TokenPair
+	TwoFactorRequired  bool
+	ChallengeToken     string
+	ChallengeExpiresAt time.Time
+}
+
+// TwoFactorSetup represents a pending TOTP enrollment
+type TwoFactorSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// TwoFactorCodeRequest represents a request carrying a TOTP code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// TwoFactorChallengeRequest represents the second step of a two-factor login
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required_without=RecoveryCode"`
+	RecoveryCode   string `json:"recovery_code"`
+}
+
+// DisableTwoFactorRequest represents the request to turn off two-factor authentication
+type DisableTwoFactorRequest struct {
+	Password     string `json:"password" validate:"required"`
+	Code         string `json:"code" validate:"required_without=RecoveryCode"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// RegenerateRecoveryCodesRequest represents the request to issue new recovery codes
+type RegenerateRecoveryCodesRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// CreateAPIKeyRequest represents the request to create a personal API key
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" validate:"required,min=1,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,oneof=read write"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"omitempty,min=1,max=365"`
+}
+
+// UpdateAPIKeyRequest represents the request to rename a personal API key
+type UpdateAPIKeyRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+// APIKeyResponse represents a personal API key without its secret
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	KeyPrefix  string     `json:"key_prefix"`
+	Scopes     []string   `json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// NewAPIKeyResponse builds the response payload of an API key
+func NewAPIKeyResponse(apiKey *model.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		KeyPrefix:  apiKey.KeyPrefix,
+		Scopes:     apiKey.ScopeList(),
+		LastUsedAt: apiKey.LastUsedAt,
+		ExpiresAt:  apiKey.ExpiresAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
+
+// UpdateProfileRequest represents the request to update the current user's profile
+type UpdateProfileRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=100"`
+}
+
+// ChangePasswordRequest represents the request to change the current user's password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+// DeleteAccountRequest represents the request to delete the current user's account
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// AccountExport is the archive of the personal data held about a user
+type AccountExport struct {
+	ExportedAt time.Time            `json:"exported_at"`
+	User       model.User           `json:"user"`
+	Sessions   []SessionResponse    `json:"sessions"`
+	APIKeys    []APIKeyResponse     `json:"api_keys"`
+	Identities []model.UserIdentity `json:"identities"`
+	Passkeys   []PasskeyResponse    `json:"passkeys"`
+}
+
+// ChangeEmailRequest represents the request to change the current user's email address
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// ConfirmEmailChangeRequest represents the email change confirmation payload
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// MagicLinkRequest represents the passwordless login request payload
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ConsumeMagicLinkRequest represents the payload exchanging a magic link token for a session
+type ConsumeMagicLinkRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// OIDCCallbackRequest represents the parameters the provider sends back after login
+type OIDCCallbackRequest struct {
+	Code             string `json:"code" query:"code" validate:"required"`
+	State            string `json:"state" query:"state" validate:"required"`
+	Error            string `json:"error" query:"error"`
+	ErrorDescription string `json:"error_description" query:"error_description"`
+}
+
+// OIDCCallbackResult represents the outcome of a provider callback. Login is set when the
+// user signed in, Identity when the external account was linked to the current user.
+type OIDCCallbackResult struct {
+	Login    *LoginResult
+	Identity *model.UserIdentity
+}
+
+// OIDCProviderResponse represents a login provider offered to clients
+type OIDCProviderResponse struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// FinishPasskeyRegistrationRequest carries the authenticator's answer to a registration challenge
+type FinishPasskeyRegistrationRequest struct {
+	Name       string          `json:"name" validate:"omitempty,max=100"`
+	Credential json.RawMessage `json:"credential" validate:"required"` // PublicKeyCredential returned by navigator.credentials.create()
+}
+
+// FinishPasskeyLoginRequest carries the authenticator's answer to a login challenge
+type FinishPasskeyLoginRequest struct {
+	Credential json.RawMessage `json:"credential" validate:"required"` // PublicKeyCredential returned by navigator.credentials.get()
+}
+
+// UpdatePasskeyRequest represents the request to rename a passkey
+type UpdatePasskeyRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+}
+
+// PasskeyResponse represents a registered passkey without its key material
+type PasskeyResponse struct {
+	ID             uint       `json:"id"`
+	Name           string     `json:"name"`
+	Transports     []string   `json:"transports"`
+	BackupEligible bool       `json:"backup_eligible"`
+	BackupState    bool       `json:"backup_state"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// NewPasskeyResponse builds the response payload of a passkey
+func NewPasskeyResponse(credential *model.WebAuthnCredential) PasskeyResponse {
+	return PasskeyResponse{
+		ID:             credential.ID,
+		Name:           credential.Name,
+		Transports:     credential.TransportList(),
+		BackupEligible: credential.BackupEligible,
+		BackupState:    credential.BackupState,
+		LastUsedAt:     credential.LastUsedAt,
+		CreatedAt:      credential.CreatedAt,
+	}
 }
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+	"go-api/domain/auth/service"
+	"go-api/model"
+	"go-api/shared/logger"
+	"go-api/shared/response"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *AuthHandler) UnlockUser(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid user ID")
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.AuthService.UnlockUser(ctx, uint(id)); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return response.NotFound(c, "User not found")
+		}
+		return response.InternalServerError(c, err, "Failed to unlock user")
+	}
+
+	logger.Infof("User %d unlocked by admin %v", id, c.Locals("user_id"))
+
+	return response.Success(c, nil, "User unlocked successfully")
+}
+
+func (h *AuthHandler) ImpersonateUser(c *fiber.Ctx) error {
+	adminID := c.Locals("user_id")
+	if adminID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid user ID")
+	}
+
+	ctx := c.UserContext()
+
+	admin := c.Locals("user").(model.User)
+	accessToken, err := h.AuthService.Impersonate(ctx, &admin, uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			return response.NotFound(c, "User not found")
+		case errors.Is(err, service.ErrCannotImpersonate):
+			return response.Forbidden(c, err.Error())
+		default:
+			return response.InternalServerError(c, err, "Failed to impersonate user")
+		}
+	}
+
+	logger.Infof("Admin %v started impersonating user %d from IP %s, token expires at %s",
+		adminID, id, c.IP(), accessToken.ExpiresAt.Format(time.RFC3339))
+
+	return response.Success(c, fiber.Map{
+		"access_token":    accessToken.Token,
+		"expires_at":      accessToken.ExpiresAt,
+		"impersonator_id": adminID,
+		"user":            accessToken.User,
+	}, "Impersonation started")
+}
+
+// StopImpersonation ends an impersonation by revoking the token used for the request
+func (h *AuthHandler) StopImpersonation(c *fiber.Ctx) error {
+	accessToken, _ := c.Locals("access_token").(*model.AccessToken)
+
+	ctx := c.UserContext()
+
+	if err := h.AuthService.StopImpersonation(ctx, accessToken); err != nil {
+		if errors.Is(err, service.ErrNotImpersonating) {
+			return response.BadRequest(c, err, "Not impersonating")
+		}
+		return response.InternalServerError(c, err, "Failed to stop impersonation")
+	}
+
+	logger.Infof("Admin %d stopped impersonating user %d", *accessToken.ImpersonatorID, accessToken.UserID)
+
+	return response.Success(c, nil, "Impersonation stopped")
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+	"go-api/domain/auth/entity"
+	"go-api/domain/auth/service"
+	"go-api/shared/response"
+	"go-api/shared/validator"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *AuthHandler) CreateAPIKey(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	var req entity.CreateAPIKeyRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	expiresIn := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	key, apiKey, err := h.AuthService.CreateAPIKey(ctx, userID.(uint), req.Name, req.Scopes, expiresIn)
+	if err != nil {
+		return response.InternalServerError(c, err, "Failed to create API key")
+	}
+
+	// The raw key is only shown once
+	return response.Created(c, fiber.Map{
+		"key":     key,
+		"api_key": entity.NewAPIKeyResponse(apiKey),
+	}, "API key created successfully. Store the key now, it will not be shown again")
+}
+
+func (h *AuthHandler) ListAPIKeys(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	ctx := c.UserContext()
+
+	apiKeys, err := h.AuthService.ListAPIKeys(ctx, userID.(uint))
+	if err != nil {
+		return response.InternalServerError(c, err, "Failed to retrieve API keys")
+	}
+
+	result := make([]entity.APIKeyResponse, 0, len(apiKeys))
+	for i := range apiKeys {
+		result = append(result, entity.NewAPIKeyResponse(&apiKeys[i]))
+	}
+
+	return response.Success(c, result, "API keys retrieved successfully")
+}
+
+func (h *AuthHandler) GetAPIKey(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid API key ID")
+	}
+
+	ctx := c.UserContext()
+
+	apiKey, err := h.AuthService.GetAPIKey(ctx, userID.(uint), uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			return response.NotFound(c, "API key not found")
+		}
+		return response.InternalServerError(c, err, "Failed to retrieve API key")
+	}
+
+	return response.Success(c, entity.NewAPIKeyResponse(apiKey), "API key retrieved successfully")
+}
+
+func (h *AuthHandler) UpdateAPIKey(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid API key ID")
+	}
+
+	var req entity.UpdateAPIKeyRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	apiKey, err := h.AuthService.RenameAPIKey(ctx, userID.(uint), uint(id), req.Name)
+	if err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			return response.NotFound(c, "API key not found")
+		}
+		return response.InternalServerError(c, err, "Failed to update API key")
+	}
+
+	return response.Success(c, entity.NewAPIKeyResponse(apiKey), "API key updated successfully")
+}
+
+func (h *AuthHandler) DeleteAPIKey(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid API key ID")
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.AuthService.DeleteAPIKey(ctx, userID.(uint), uint(id)); err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			return response.NotFound(c, "API key not found")
+		}
+		return response.InternalServerError(c, err, "Failed to delete API key")
+	}
+
+	return response.Success(c, nil, "API key revoked successfully")
+}
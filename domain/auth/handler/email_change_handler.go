@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+	"go-api/domain/auth/entity"
+	"go-api/domain/auth/service"
+	"go-api/shared/response"
+	"go-api/shared/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *AuthHandler) RequestEmailChange(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	var req entity.ChangeEmailRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.AuthService.RequestEmailChange(ctx, userID.(uint), req.Password, req.NewEmail); err != nil {
+		switch {
+		case errors.Is(err, service.ErrAccountLocked):
+			return response.Error(c, fiber.StatusTooManyRequests, err, "Account locked")
+		case errors.Is(err, service.ErrInvalidPassword),
+			errors.Is(err, service.ErrEmailUnchanged),
+			errors.Is(err, service.ErrEmailTaken):
+			return response.BadRequest(c, err, "Email change failed")
+		default:
+			return response.InternalServerError(c, err, "Email change failed")
+		}
+	}
+
+	return response.Success(c, nil, "A confirmation code has been sent to the new email address")
+}
+
+func (h *AuthHandler) ConfirmEmailChange(c *fiber.Ctx) error {
+	var req entity.ConfirmEmailChangeRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.AuthService.ConfirmEmailChange(ctx, req.Token); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidEmailChangeToken), errors.Is(err, service.ErrEmailTaken):
+			return response.BadRequest(c, err, "Email change confirmation failed")
+		default:
+			return response.InternalServerError(c, err, "Email change confirmation failed")
+		}
+	}
+
+	return response.Success(c, nil, "Email address changed successfully. Please sign in again")
+}
@@ -1,12 +1,17 @@
 package handler
 
 import (
+	"errors"
 	"go-api/app"
 	"go-api/domain/auth/entity"
 	"go-api/domain/auth/service"
 	"go-api/email"
+	"go-api/model"
+	"go-api/passwordhash"
+	"go-api/shared/logger"
 	"go-api/shared/response"
 	"go-api/shared/validator"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -37,16 +42,74 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	// Gunakan context dari Fiber yang sudah memiliki timeout dari middleware
 	ctx := c.UserContext()
 	// Call service with timeout context
-	accessToken, err := h.AuthService.Login(ctx, req.Email, req.Password)
+	result, err := h.AuthService.Login(ctx, req.Email, req.Password)
 	if err != nil {
-		return response.Unauthorized(c, err.Error())
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			return response.Unauthorized(c, err.Error())
+		}
+		return response.InternalServerError(c, err, "Login failed")
 	}
 
-	return response.Success(c, fiber.Map{
-		"access_token": accessToken.Token,
-		"expires_at":   accessToken.ExpiresAt,
-		"user":         accessToken.User,
-	}, "Login successful")
+	return h.loginResultResponse(c, result)
+}
+
+// loginResultResponse responds with either a two-factor challenge or the issued tokens
+func (h *AuthHandler) loginResultResponse(c *fiber.Ctx, result *entity.LoginResult) error {
+	// The client must complete the two-factor challenge before receiving tokens
+	if result.TwoFactorRequired {
+		return response.Success(c, fiber.Map{
+			"two_factor_required":  true,
+			"challenge_token":      result.ChallengeToken,
+			"challenge_expires_at": result.ChallengeExpiresAt,
+		}, "Two-factor authentication required")
+	}
+
+	tokens := result.Tokens
+
+	// Capture the device the session was started from
+	if err := h.AuthService.RecordTokenUsage(c.UserContext(), tokens.AccessToken, c.IP(), c.Get("User-Agent")); err != nil {
+		logger.Warnf("Failed to record session details: %v", err)
+	}
+
+	return response.Success(c, tokenPairResponse(tokens), "Login successful")
+}
+
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var req entity.RefreshTokenRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	tokens, err := h.AuthService.Refresh(ctx, req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) ||
+			errors.Is(err, service.ErrRefreshTokenReused) ||
+			errors.Is(err, service.ErrSessionExpired) {
+			return response.Unauthorized(c, err.Error())
+		}
+		return response.InternalServerError(c, err, "Failed to refresh token")
+	}
+
+	return response.Success(c, tokenPairResponse(tokens), "Token refreshed successfully")
+}
+
+// tokenPairResponse builds the response payload shared by login and refresh
+func tokenPairResponse(tokens *entity.TokenPair) fiber.Map {
+	return fiber.Map{
+		"access_token":       tokens.AccessToken.Token,
+		"expires_at":         tokens.AccessToken.ExpiresAt,
+		"refresh_token":      tokens.RefreshToken,
+		"refresh_expires_at": tokens.RefreshExpiresAt,
+		"user":               tokens.AccessToken.User,
+	}
 }
 
 func (h *AuthHandler) Register(c *fiber.Ctx) error {
@@ -61,15 +124,14 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 		return response.ValidationError(c, validationErrors)
 	}
 
-	// Additional password validation
-	if passwordErrors := validator.ValidatePasswordWithDetails(req.Password); passwordErrors != nil {
-		return response.ValidationError(c, passwordErrors)
-	}
-
 	// Gunakan context dari Fiber yang sudah memiliki timeout dari middleware
 	ctx := c.UserContext()
 	// Call service with context
 	if err := h.AuthService.Register(ctx, &req); err != nil {
+		var policyErr *service.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			return response.ValidationError(c, map[string][]string{"password": policyErr.Messages})
+		}
 		return response.BadRequest(c, err, "Registration failed")
 	}
 
@@ -96,6 +158,10 @@ func (h *AuthHandler) Logout(c *fiber.Ctx) error {
 		return response.InternalServerError(c, err, "Failed to logout")
 	}
 
+	if impersonatorID, ok := c.Locals("impersonator_id").(uint); ok {
+		logger.Infof("Admin %d stopped impersonating user %v", impersonatorID, c.Locals("user_id"))
+	}
+
 	return response.Success(c, nil, "Logged out successfully")
 }
 
@@ -115,3 +181,143 @@ func (h *AuthHandler) LogoutAll(c *fiber.Ctx) error {
 
 	return response.Success(c, nil, "Logged out from all devices successfully")
 }
+
+func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	var req entity.ForgotPasswordRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.AuthService.ForgotPassword(ctx, req.Email); err != nil {
+		return response.InternalServerError(c, err, "Failed to process password reset request")
+	}
+
+	// Same response whether or not the email exists
+	return response.Success(c, nil, "If the email is registered, a password reset link has been sent")
+}
+
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	var req entity.ResetPasswordRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request structure
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.AuthService.ResetPassword(ctx, req.Token, req.Password); err != nil {
+		var policyErr *service.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			return response.ValidationError(c, map[string][]string{"password": policyErr.Messages})
+		}
+		if errors.Is(err, service.ErrInvalidResetToken) || errors.Is(err, passwordhash.ErrPasswordTooLong) {
+			return response.BadRequest(c, err, "Password reset failed")
+		}
+		return response.InternalServerError(c, err, "Password reset failed")
+	}
+
+	return response.Success(c, nil, "Password has been reset successfully")
+}
+
+func (h *AuthHandler) VerifyEmail(c *fiber.Ctx) error {
+	var req entity.VerifyEmailRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.AuthService.VerifyEmail(ctx, req.Token); err != nil {
+		if errors.Is(err, service.ErrInvalidVerificationToken) {
+			return response.BadRequest(c, err, "Email verification failed")
+		}
+		return response.InternalServerError(c, err, "Email verification failed")
+	}
+
+	return response.Success(c, nil, "Email verified successfully")
+}
+
+func (h *AuthHandler) ResendVerificationEmail(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.AuthService.ResendVerificationEmail(ctx, userID.(uint)); err != nil {
+		switch {
+		case errors.Is(err, service.ErrEmailAlreadyVerified):
+			return response.BadRequest(c, err, "Email is already verified")
+		case errors.Is(err, service.ErrVerificationThrottled):
+			return response.Error(c, fiber.StatusTooManyRequests, err, "Too many verification requests")
+		default:
+			return response.InternalServerError(c, err, "Failed to send verification email")
+		}
+	}
+
+	return response.Success(c, nil, "Verification email sent")
+}
+
+func (h *AuthHandler) ListSessions(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	ctx := c.UserContext()
+
+	current, _ := c.Locals("access_token").(*model.AccessToken)
+
+	sessions, err := h.AuthService.ListSessions(ctx, userID.(uint), current)
+	if err != nil {
+		return response.InternalServerError(c, err, "Failed to retrieve sessions")
+	}
+
+	return response.Success(c, sessions, "Sessions retrieved successfully")
+}
+
+func (h *AuthHandler) RevokeSession(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	sessionID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid session ID")
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.AuthService.RevokeSession(ctx, userID.(uint), uint(sessionID)); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			return response.NotFound(c, "Session not found")
+		}
+		return response.InternalServerError(c, err, "Failed to revoke session")
+	}
+
+	return response.Success(c, nil, "Session revoked successfully")
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"go-api/domain/auth/entity"
+	"go-api/domain/auth/service"
+	"go-api/shared/response"
+	"go-api/shared/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *AuthHandler) SendMagicLink(c *fiber.Ctx) error {
+	var req entity.MagicLinkRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.AuthService.SendMagicLink(ctx, req.Email); err != nil {
+		return response.InternalServerError(c, err, "Failed to send login link")
+	}
+
+	// Same response whether or not the email exists
+	return response.Success(c, nil, "If the email is registered, a login link has been sent")
+}
+
+func (h *AuthHandler) ConsumeMagicLink(c *fiber.Ctx) error {
+	var req entity.ConsumeMagicLinkRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	result, err := h.AuthService.ConsumeMagicLink(ctx, req.Token)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidMagicLinkToken):
+			return response.Unauthorized(c, err.Error())
+		case errors.Is(err, service.ErrAccountLocked):
+			return response.Error(c, fiber.StatusTooManyRequests, err, "Account locked")
+		default:
+			return response.InternalServerError(c, err, "Login failed")
+		}
+	}
+
+	return h.loginResultResponse(c, result)
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"go-api/domain/auth/entity"
+	"go-api/domain/auth/service"
+	"go-api/shared/response"
+	"go-api/shared/validator"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *AuthHandler) ListOIDCProviders(c *fiber.Ctx) error {
+	return response.Success(c, h.AuthService.ListOIDCProviders(), "Login providers retrieved successfully")
+}
+
+func (h *AuthHandler) StartOIDCLogin(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	authorizationURL, err := h.AuthService.StartOIDCLogin(ctx, c.Params("provider"), nil)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownOIDCProvider) {
+			return response.NotFound(c, "Login provider not found")
+		}
+		return response.InternalServerError(c, err, "Failed to start login")
+	}
+
+	return response.Success(c, fiber.Map{"authorization_url": authorizationURL}, "Redirect the user to the authorization URL")
+}
+
+func (h *AuthHandler) LinkOIDCIdentity(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	ctx := c.UserContext()
+
+	id := userID.(uint)
+	authorizationURL, err := h.AuthService.StartOIDCLogin(ctx, c.Params("provider"), &id)
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownOIDCProvider) {
+			return response.NotFound(c, "Login provider not found")
+		}
+		return response.InternalServerError(c, err, "Failed to start account linking")
+	}
+
+	return response.Success(c, fiber.Map{"authorization_url": authorizationURL}, "Redirect the user to the authorization URL")
+}
+
+// OIDCCallback accepts the provider redirect (GET with query parameters) as well as
+// a frontend forwarding the same parameters (POST with a JSON body). It only completes logins,
+// linking is completed by the authenticated CompleteOIDCLink.
+func (h *AuthHandler) OIDCCallback(c *fiber.Ctx) error {
+	var req entity.OIDCCallbackRequest
+	if c.Method() == fiber.MethodGet {
+		if err := c.QueryParser(&req); err != nil {
+			return response.BadRequest(c, err, "Invalid callback parameters")
+		}
+	} else if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	return h.completeOIDCCallback(c, &req, nil)
+}
+
+// CompleteOIDCLink completes linking for the signed in user, the frontend forwards the callback
+// parameters of a flow started with LinkOIDCIdentity
+func (h *AuthHandler) CompleteOIDCLink(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	var req entity.OIDCCallbackRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	id := userID.(uint)
+	return h.completeOIDCCallback(c, &req, &id)
+}
+
+// completeOIDCCallback completes a login, or a link for userID
+func (h *AuthHandler) completeOIDCCallback(c *fiber.Ctx, req *entity.OIDCCallbackRequest, userID *uint) error {
+	// The user denied access or the provider rejected the request
+	if req.Error != "" {
+		return response.BadRequest(c, errors.New(req.Error), "Login was not completed: "+req.ErrorDescription)
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	result, err := h.AuthService.CompleteOIDCLogin(ctx, c.Params("provider"), req.Code, req.State, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownOIDCProvider):
+			return response.NotFound(c, "Login provider not found")
+		case errors.Is(err, service.ErrInvalidOIDCState),
+			errors.Is(err, service.ErrOIDCLoginFailed),
+			errors.Is(err, service.ErrOIDCEmailMissing):
+			return response.Unauthorized(c, err.Error())
+		case errors.Is(err, service.ErrOIDCEmailConflict),
+			errors.Is(err, service.ErrIdentityAlreadyLinked):
+			return response.Error(c, fiber.StatusConflict, err, err.Error())
+		case errors.Is(err, service.ErrAccountLocked):
+			return response.Error(c, fiber.StatusTooManyRequests, err, "Account locked")
+		default:
+			return response.InternalServerError(c, err, "Login failed")
+		}
+	}
+
+	if result.Identity != nil {
+		return response.Success(c, result.Identity, "Account linked successfully")
+	}
+
+	return h.loginResultResponse(c, result.Login)
+}
+
+func (h *AuthHandler) ListIdentities(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	ctx := c.UserContext()
+
+	identities, err := h.AuthService.ListIdentities(ctx, userID.(uint))
+	if err != nil {
+		return response.InternalServerError(c, err, "Failed to retrieve linked accounts")
+	}
+
+	return response.Success(c, identities, "Linked accounts retrieved successfully")
+}
+
+func (h *AuthHandler) UnlinkIdentity(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid identity ID")
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.AuthService.UnlinkIdentity(ctx, userID.(uint), uint(id)); err != nil {
+		switch {
+		case errors.Is(err, service.ErrIdentityNotFound):
+			return response.NotFound(c, "Linked account not found")
+		case errors.Is(err, service.ErrLastLoginMethod):
+			return response.BadRequest(c, err, "Failed to unlink account")
+		default:
+			return response.InternalServerError(c, err, "Failed to unlink account")
+		}
+	}
+
+	return response.Success(c, nil, "Account unlinked successfully")
+}
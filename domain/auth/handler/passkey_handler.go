@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"errors"
+	"go-api/domain/auth/entity"
+	"go-api/domain/auth/service"
+	"go-api/shared/response"
+	"go-api/shared/validator"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BeginPasskeyRegistration returns the options for navigator.credentials.create()
+func (h *AuthHandler) BeginPasskeyRegistration(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	ctx := c.UserContext()
+
+	options, err := h.AuthService.BeginPasskeyRegistration(ctx, userID.(uint))
+	if err != nil {
+		if errors.Is(err, service.ErrPasskeysDisabled) {
+			return response.NotFound(c, err.Error())
+		}
+		return response.InternalServerError(c, err, "Failed to start passkey registration")
+	}
+
+	return response.Success(c, options, "Passkey registration started")
+}
+
+func (h *AuthHandler) FinishPasskeyRegistration(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	var req entity.FinishPasskeyRegistrationRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	credential, err := h.AuthService.FinishPasskeyRegistration(ctx, userID.(uint), req.Name, req.Credential)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPasskeysDisabled):
+			return response.NotFound(c, err.Error())
+		case errors.Is(err, service.ErrInvalidPasskeyChallenge),
+			errors.Is(err, service.ErrPasskeyVerificationFailed),
+			errors.Is(err, service.ErrPasskeyAlreadyRegistered):
+			return response.BadRequest(c, err, "Passkey registration failed")
+		default:
+			return response.InternalServerError(c, err, "Failed to register passkey")
+		}
+	}
+
+	return response.Created(c, entity.NewPasskeyResponse(credential), "Passkey registered successfully")
+}
+
+// BeginPasskeyLogin returns the options for navigator.credentials.get()
+func (h *AuthHandler) BeginPasskeyLogin(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	options, err := h.AuthService.BeginPasskeyLogin(ctx)
+	if err != nil {
+		if errors.Is(err, service.ErrPasskeysDisabled) {
+			return response.NotFound(c, err.Error())
+		}
+		return response.InternalServerError(c, err, "Failed to start passkey login")
+	}
+
+	return response.Success(c, options, "Passkey login started")
+}
+
+func (h *AuthHandler) FinishPasskeyLogin(c *fiber.Ctx) error {
+	var req entity.FinishPasskeyLoginRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	result, err := h.AuthService.FinishPasskeyLogin(ctx, req.Credential)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPasskeysDisabled):
+			return response.NotFound(c, err.Error())
+		case errors.Is(err, service.ErrInvalidPasskeyChallenge),
+			errors.Is(err, service.ErrPasskeyVerificationFailed):
+			return response.Unauthorized(c, err.Error())
+		case errors.Is(err, service.ErrAccountLocked):
+			return response.Error(c, fiber.StatusTooManyRequests, err, "Account locked")
+		default:
+			return response.InternalServerError(c, err, "Login failed")
+		}
+	}
+
+	return h.loginResultResponse(c, result)
+}
+
+func (h *AuthHandler) ListPasskeys(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	ctx := c.UserContext()
+
+	credentials, err := h.AuthService.ListPasskeys(ctx, userID.(uint))
+	if err != nil {
+		return response.InternalServerError(c, err, "Failed to retrieve passkeys")
+	}
+
+	result := make([]entity.PasskeyResponse, 0, len(credentials))
+	for i := range credentials {
+		result = append(result, entity.NewPasskeyResponse(&credentials[i]))
+	}
+
+	return response.Success(c, result, "Passkeys retrieved successfully")
+}
+
+func (h *AuthHandler) UpdatePasskey(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid passkey ID")
+	}
+
+	var req entity.UpdatePasskeyRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	credential, err := h.AuthService.RenamePasskey(ctx, userID.(uint), uint(id), req.Name)
+	if err != nil {
+		if errors.Is(err, service.ErrPasskeyNotFound) {
+			return response.NotFound(c, "Passkey not found")
+		}
+		return response.InternalServerError(c, err, "Failed to update passkey")
+	}
+
+	return response.Success(c, entity.NewPasskeyResponse(credential), "Passkey updated successfully")
+}
+
+func (h *AuthHandler) DeletePasskey(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid passkey ID")
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.AuthService.DeletePasskey(ctx, userID.(uint), uint(id)); err != nil {
+		if errors.Is(err, service.ErrPasskeyNotFound) {
+			return response.NotFound(c, "Passkey not found")
+		}
+		return response.InternalServerError(c, err, "Failed to delete passkey")
+	}
+
+	return response.Success(c, nil, "Passkey removed successfully")
+}
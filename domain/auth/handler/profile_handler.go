@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"go-api/domain/auth/entity"
+	"go-api/domain/auth/service"
+	"go-api/model"
+	"go-api/passwordhash"
+	"go-api/shared/constant"
+	"go-api/shared/logger"
+	"go-api/shared/response"
+	"go-api/shared/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *AuthHandler) Me(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	ctx := c.UserContext()
+
+	// Load a fresh copy, the token may only carry a subset of the user (JWT mode)
+	user, err := h.AuthService.GetProfile(ctx, userID.(uint))
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return response.NotFound(c, "User not found")
+		}
+		return response.InternalServerError(c, err, "Failed to retrieve profile")
+	}
+
+	// OAuth clients only see the email address when the user granted it
+	if oauthToken, ok := c.Locals("oauth_token").(*model.OAuthToken); ok && !oauthToken.HasScope(constant.ScopeEmail) {
+		user.Email = ""
+		user.EmailVerifiedAt = nil
+	}
+
+	return response.Success(c, user, "Profile retrieved successfully")
+}
+
+func (h *AuthHandler) UpdateMe(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	var req entity.UpdateProfileRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	user, err := h.AuthService.UpdateProfile(ctx, userID.(uint), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return response.NotFound(c, "User not found")
+		}
+		return response.InternalServerError(c, err, "Failed to update profile")
+	}
+
+	return response.Success(c, user, "Profile updated successfully")
+}
+
+func (h *AuthHandler) ChangePassword(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	var req entity.ChangePasswordRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request structure
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	tokens, err := h.AuthService.ChangePassword(ctx, userID.(uint), req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		var policyErr *service.PasswordPolicyError
+		switch {
+		case errors.As(err, &policyErr):
+			// Report the messages under the field name used in this request
+			return response.ValidationError(c, map[string][]string{"new_password": policyErr.Messages})
+		case errors.Is(err, service.ErrAccountLocked):
+			return response.Error(c, fiber.StatusTooManyRequests, err, "Account locked")
+		case errors.Is(err, service.ErrInvalidPassword),
+			errors.Is(err, service.ErrPasswordUnchanged),
+			errors.Is(err, passwordhash.ErrPasswordTooLong):
+			return response.BadRequest(c, err, "Password change failed")
+		default:
+			return response.InternalServerError(c, err, "Password change failed")
+		}
+	}
+
+	// Other sessions were signed out, the caller continues with the new tokens
+	if err := h.AuthService.RecordTokenUsage(ctx, tokens.AccessToken, c.IP(), c.Get("User-Agent")); err != nil {
+		logger.Warnf("Failed to record session details: %v", err)
+	}
+
+	return response.Success(c, tokenPairResponse(tokens), "Password changed successfully")
+}
+
+func (h *AuthHandler) DeleteMe(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	var req entity.DeleteAccountRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request structure
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	purgeAt, err := h.AuthService.DeleteAccount(ctx, userID.(uint), req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAccountLocked):
+			return response.Error(c, fiber.StatusTooManyRequests, err, "Account locked")
+		case errors.Is(err, service.ErrInvalidPassword):
+			return response.BadRequest(c, err, "Account deletion failed")
+		default:
+			return response.InternalServerError(c, err, "Account deletion failed")
+		}
+	}
+
+	return response.Success(c, fiber.Map{
+		"deletion_scheduled_at": purgeAt,
+	}, "Account scheduled for deletion, sign in again before the deletion date to cancel it")
+}
+
+func (h *AuthHandler) ExportMe(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	ctx := c.UserContext()
+
+	export, err := h.AuthService.ExportAccount(ctx, userID.(uint))
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			return response.NotFound(c, "User not found")
+		}
+		return response.InternalServerError(c, err, "Failed to export account data")
+	}
+
+	logger.Infof("Account data of user %d exported from IP %s", export.User.ID, c.IP())
+
+	// Served as a file download rather than inside the response envelope
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="account-export-%d.json"`, export.User.ID))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(export)
+}
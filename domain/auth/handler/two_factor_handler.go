@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"errors"
+	"go-api/domain/auth/entity"
+	"go-api/domain/auth/service"
+	"go-api/shared/logger"
+	"go-api/shared/response"
+	"go-api/shared/validator"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *AuthHandler) SetupTwoFactor(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	ctx := c.UserContext()
+
+	setup, err := h.AuthService.SetupTwoFactor(ctx, userID.(uint))
+	if err != nil {
+		if errors.Is(err, service.ErrTwoFactorAlreadyEnabled) {
+			return response.BadRequest(c, err, "Two-factor setup failed")
+		}
+		return response.InternalServerError(c, err, "Two-factor setup failed")
+	}
+
+	return response.Success(c, setup, "Scan the secret with your authenticator app and confirm with a code")
+}
+
+func (h *AuthHandler) ConfirmTwoFactor(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	var req entity.TwoFactorCodeRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	codes, err := h.AuthService.ConfirmTwoFactor(ctx, userID.(uint), req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTwoFactorAlreadyEnabled),
+			errors.Is(err, service.ErrTwoFactorNotPending),
+			errors.Is(err, service.ErrInvalidTwoFactorCode):
+			return response.BadRequest(c, err, "Two-factor confirmation failed")
+		default:
+			return response.InternalServerError(c, err, "Two-factor confirmation failed")
+		}
+	}
+
+	return response.Success(c, fiber.Map{"recovery_codes": codes}, "Two-factor authentication enabled")
+}
+
+func (h *AuthHandler) DisableTwoFactor(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	var req entity.DisableTwoFactorRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.AuthService.DisableTwoFactor(ctx, userID.(uint), req.Password, req.Code, req.RecoveryCode); err != nil {
+		switch {
+		case errors.Is(err, service.ErrAccountLocked):
+			return response.Error(c, fiber.StatusTooManyRequests, err, "Account locked")
+		case errors.Is(err, service.ErrInvalidPassword),
+			errors.Is(err, service.ErrTwoFactorNotEnabled),
+			errors.Is(err, service.ErrInvalidTwoFactorCode):
+			return response.BadRequest(c, err, "Failed to disable two-factor authentication")
+		default:
+			return response.InternalServerError(c, err, "Failed to disable two-factor authentication")
+		}
+	}
+
+	return response.Success(c, nil, "Two-factor authentication disabled")
+}
+
+func (h *AuthHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	var req entity.RegenerateRecoveryCodesRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	codes, err := h.AuthService.RegenerateRecoveryCodes(ctx, userID.(uint), req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAccountLocked):
+			return response.Error(c, fiber.StatusTooManyRequests, err, "Account locked")
+		case errors.Is(err, service.ErrInvalidPassword),
+			errors.Is(err, service.ErrTwoFactorNotEnabled):
+			return response.BadRequest(c, err, "Failed to regenerate recovery codes")
+		default:
+			return response.InternalServerError(c, err, "Failed to regenerate recovery codes")
+		}
+	}
+
+	return response.Success(c, fiber.Map{"recovery_codes": codes}, "Recovery codes regenerated")
+}
+
+func (h *AuthHandler) CompleteTwoFactorChallenge(c *fiber.Ctx) error {
+	var req entity.TwoFactorChallengeRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	tokens, err := h.AuthService.CompleteTwoFactorChallenge(ctx, req.ChallengeToken, req.Code, req.RecoveryCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidChallenge),
+			errors.Is(err, service.ErrInvalidTwoFactorCode),
+			errors.Is(err, service.ErrTwoFactorNotEnabled):
+			return response.Unauthorized(c, err.Error())
+		case errors.Is(err, service.ErrAccountLocked):
+			return response.Error(c, fiber.StatusTooManyRequests, err, "Account locked")
+		default:
+			return response.InternalServerError(c, err, "Two-factor login failed")
+		}
+	}
+
+	// Capture the device the session was started from
+	if err := h.AuthService.RecordTokenUsage(ctx, tokens.AccessToken, c.IP(), c.Get("User-Agent")); err != nil {
+		logger.Warnf("Failed to record session details: %v", err)
+	}
+
+	return response.Success(c, tokenPairResponse(tokens), "Login successful")
+}
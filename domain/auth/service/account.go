@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"go-api/config"
+	"go-api/domain/auth/entity"
+	"go-api/model"
+	"go-api/repository"
+	"go-api/shared/logger"
+	"go-api/shared/timezone"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// purgeBatchSize limits how many accounts are loaded at once when purging
+const purgeBatchSize = 100
+
+// DeleteAccount schedules the account for purging once the grace period is over and signs the
+// user out everywhere. Signing in again before then cancels the deletion.
+func (s *AuthService) DeleteAccount(ctx context.Context, userID uint, password string) (time.Time, error) {
+	user, err := s.verifyPassword(ctx, userID, password)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	purgeAt := timezone.Now().Add(config.Get().AccountDeletionGracePeriod)
+
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewUserRepository(tx).ScheduleDeletion(ctx, userID, purgeAt); err != nil {
+			return err
+		}
+
+		// Keys and third-party access are not restored when the deletion is cancelled
+		if err := repository.NewAPIKeyRepository(tx).DeleteAllForUser(ctx, userID); err != nil {
+			return err
+		}
+		if err := repository.NewOAuthTokenRepository(tx).RevokeAllForUser(ctx, userID); err != nil {
+			return err
+		}
+
+		return s.revokeAllUserSessions(ctx, tx, userID)
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	logger.Infof("Account %d scheduled for deletion at %s", userID, purgeAt.Format(time.RFC3339))
+
+	go func() {
+		if err := s.provider.Email.SendAccountDeletionScheduledEmail(user.Email, user.Name, purgeAt); err != nil {
+			logger.Errorf("Failed to send account deletion email: %v", err)
+		}
+	}()
+
+	return purgeAt, nil
+}
+
+// cancelAccountDeletion keeps an account whose owner signed in during the grace period
+func (s *AuthService) cancelAccountDeletion(ctx context.Context, user *model.User) error {
+	if !user.IsDeletionScheduled() {
+		return nil
+	}
+
+	if err := s.userRepo.CancelDeletion(ctx, user.ID); err != nil {
+		return err
+	}
+	user.DeletionScheduledAt = nil
+
+	logger.Infof("Scheduled deletion of account %d cancelled by signing in", user.ID)
+	return nil
+}
+
+// ExportAccount collects the personal data held about a user into a single archive
+func (s *AuthService) ExportAccount(ctx context.Context, userID uint) (*entity.AccountExport, error) {
+	user, err := s.GetProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.ListSessions(ctx, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeys, err := s.ListAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := s.ListIdentities(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	passkeys, err := s.ListPasskeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &entity.AccountExport{
+		ExportedAt: timezone.Now(),
+		User:       *user,
+		Sessions:   sessions,
+		APIKeys:    make([]entity.APIKeyResponse, 0, len(apiKeys)),
+		Identities: identities,
+		Passkeys:   make([]entity.PasskeyResponse, 0, len(passkeys)),
+	}
+	for i := range apiKeys {
+		export.APIKeys = append(export.APIKeys, entity.NewAPIKeyResponse(&apiKeys[i]))
+	}
+	for i := range passkeys {
+		export.Passkeys = append(export.Passkeys, entity.NewPasskeyResponse(&passkeys[i]))
+	}
+
+	return export, nil
+}
+
+// PurgeDeletedAccounts permanently deletes every account whose grace period has ended and
+// returns how many were purged
+func (s *AuthService) PurgeDeletedAccounts(ctx context.Context) (int, error) {
+	purged := 0
+	for {
+		users, err := s.userRepo.FindDueForPurge(ctx, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+		if len(users) == 0 {
+			return purged, nil
+		}
+
+		for _, user := range users {
+			if err := s.userRepo.Purge(ctx, user.ID); err != nil {
+				return purged, err
+			}
+			logger.Infof("Account %d purged after scheduled deletion", user.ID)
+			purged++
+		}
+	}
+}
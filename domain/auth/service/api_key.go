@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-api/config"
+	"go-api/model"
+	"go-api/repository"
+	"go-api/shared/timezone"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrInvalidAPIKey  = errors.New("invalid or expired api key")
+)
+
+// CreateAPIKey issues a personal API key. The raw key is returned once and only its digest is stored.
+func (s *AuthService) CreateAPIKey(ctx context.Context, userID uint, name string, scopes []string, expiresIn time.Duration) (string, *model.APIKey, error) {
+	var expiresAt *time.Time
+	if expiresIn > 0 {
+		t := timezone.Now().Add(expiresIn)
+		expiresAt = &t
+	}
+
+	return repository.NewAPIKeyRepository(s.provider.DB).Create(ctx, userID, strings.TrimSpace(name), uniqueScopes(scopes), expiresAt)
+}
+
+// ListAPIKeys returns the API keys of a user
+func (s *AuthService) ListAPIKeys(ctx context.Context, userID uint) ([]model.APIKey, error) {
+	return repository.NewAPIKeyRepository(s.provider.DB).FindByUser(ctx, userID)
+}
+
+func (s *AuthService) GetAPIKey(ctx context.Context, userID, id uint) (*model.APIKey, error) {
+	apiKey, err := repository.NewAPIKeyRepository(s.provider.DB).FindByIDForUser(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+	return apiKey, nil
+}
+
+// RenameAPIKey changes the display name of a key. Scopes and expiry are fixed at creation.
+func (s *AuthService) RenameAPIKey(ctx context.Context, userID, id uint, name string) (*model.APIKey, error) {
+	apiKey, err := s.GetAPIKey(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey.Name = strings.TrimSpace(name)
+	if err := repository.NewAPIKeyRepository(s.provider.DB).UpdateName(ctx, apiKey.ID, apiKey.Name); err != nil {
+		return nil, err
+	}
+
+	return apiKey, nil
+}
+
+func (s *AuthService) DeleteAPIKey(ctx context.Context, userID, id uint) error {
+	if err := repository.NewAPIKeyRepository(s.provider.DB).Delete(ctx, id, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ValidateAPIKey resolves a raw key presented in the X-API-Key header
+func (s *AuthService) ValidateAPIKey(ctx context.Context, key string) (*model.APIKey, error) {
+	if !strings.HasPrefix(key, model.APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := repository.NewAPIKeyRepository(s.provider.DB).FindByKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if !apiKey.IsValid() {
+		return nil, ErrInvalidAPIKey
+	}
+
+	return apiKey, nil
+}
+
+// RecordAPIKeyUsage updates the last use time of a key, at most once per touch interval
+func (s *AuthService) RecordAPIKeyUsage(ctx context.Context, apiKey *model.APIKey) error {
+	if apiKey.LastUsedAt != nil && timezone.Now().Before(apiKey.LastUsedAt.Add(config.Get().TokenTouchInterval)) {
+		return nil
+	}
+	return repository.NewAPIKeyRepository(s.provider.DB).Touch(ctx, apiKey.ID)
+}
+
+// uniqueScopes removes duplicate scopes while keeping their order
+func uniqueScopes(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	return result
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-api/config"
+	"go-api/repository"
+	"go-api/shared/logger"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEmailTaken              = errors.New("email address is already in use")
+	ErrEmailUnchanged          = errors.New("new email address must be different from the current one")
+	ErrInvalidEmailChangeToken = errors.New("invalid or expired email change token")
+)
+
+// RequestEmailChange stores the new address as pending, emails a confirmation code to it
+// and warns the current address. The email is only swapped by ConfirmEmailChange.
+func (s *AuthService) RequestEmailChange(ctx context.Context, userID uint, password, newEmail string) error {
+	user, err := s.verifyPassword(ctx, userID, password)
+	if err != nil {
+		return err
+	}
+
+	newEmail = strings.TrimSpace(newEmail)
+	if strings.EqualFold(newEmail, user.Email) {
+		return ErrEmailUnchanged
+	}
+
+	exists, err := s.userRepo.EmailExists(ctx, newEmail)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrEmailTaken
+	}
+
+	changeTokenRepo := repository.NewEmailChangeTokenRepository(s.provider.DB)
+
+	// Only the latest requested address can be confirmed
+	if err := changeTokenRepo.RevokeAllUserTokens(ctx, user.ID); err != nil {
+		return err
+	}
+
+	expiry := config.Get().EmailChangeExpiry
+	token, _, err := changeTokenRepo.Create(ctx, user.ID, newEmail, expiry)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := s.provider.Email.SendEmailChangeConfirmationEmail(newEmail, user.Name, token, int(expiry.Minutes())); err != nil {
+			logger.Errorf("Failed to send email change confirmation: %v", err)
+		}
+		if err := s.provider.Email.SendEmailChangeNoticeEmail(user.Email, user.Name, newEmail); err != nil {
+			logger.Errorf("Failed to send email change notice: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// ConfirmEmailChange swaps the email address of the token owner and signs them out everywhere
+func (s *AuthService) ConfirmEmailChange(ctx context.Context, token string) error {
+	changeToken, err := repository.NewEmailChangeTokenRepository(s.provider.DB).FindByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidEmailChangeToken
+		}
+		return err
+	}
+
+	if !changeToken.IsValid() || isDeletedUser(&changeToken.User) {
+		return ErrInvalidEmailChangeToken
+	}
+
+	return s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		changeTokenRepo := repository.NewEmailChangeTokenRepository(tx)
+		if err := changeTokenRepo.MarkUsed(ctx, changeToken.ID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidEmailChangeToken
+			}
+			return err
+		}
+
+		userRepo := repository.NewUserRepository(tx)
+
+		// The address may have been registered since the change was requested
+		exists, err := userRepo.EmailExists(ctx, changeToken.NewEmail)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrEmailTaken
+		}
+
+		if err := userRepo.UpdateEmail(ctx, changeToken.UserID, changeToken.NewEmail); err != nil {
+			return err
+		}
+
+		if err := changeTokenRepo.RevokeAllUserTokens(ctx, changeToken.UserID); err != nil {
+			return err
+		}
+
+		// Outstanding links were sent to the old address
+		if err := repository.NewPasswordResetTokenRepository(tx).RevokeAllUserTokens(ctx, changeToken.UserID); err != nil {
+			return err
+		}
+		if err := repository.NewMagicLinkTokenRepository(tx).RevokeAllUserTokens(ctx, changeToken.UserID); err != nil {
+			return err
+		}
+		if err := repository.NewEmailVerificationTokenRepository(tx).RevokeAllUserTokens(ctx, changeToken.UserID); err != nil {
+			return err
+		}
+
+		return s.revokeAllUserSessions(ctx, tx, changeToken.UserID)
+	})
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-api/config"
+	"go-api/model"
+	"go-api/shared/constant"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCannotImpersonate = errors.New("this user cannot be impersonated")
+	ErrNotImpersonating  = errors.New("the current token is not an impersonation token")
+)
+
+// Impersonate issues a short-lived token that lets an admin act as another user. The token
+// carries the admin's ID, cannot be refreshed and is refused on sensitive endpoints.
+func (s *AuthService) Impersonate(ctx context.Context, admin *model.User, userID uint) (*model.AccessToken, error) {
+	if admin.ID == userID {
+		return nil, ErrCannotImpersonate
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	// Acting as another admin would hide admin actions behind someone else's name
+	if user.Role.Code == constant.RoleCodeAdmin {
+		return nil, ErrCannotImpersonate
+	}
+
+	// Nor may the token give the admin permissions they do not hold themselves
+	covered, err := s.provider.Permissions.CoversRole(ctx, admin, &user.Role)
+	if err != nil {
+		return nil, err
+	}
+	if !covered {
+		return nil, ErrCannotImpersonate
+	}
+
+	return s.tokens.IssueImpersonation(ctx, user, admin.ID, config.Get().ImpersonationExpiry)
+}
+
+// StopImpersonation revokes the impersonation token the admin is using
+func (s *AuthService) StopImpersonation(ctx context.Context, accessToken *model.AccessToken) error {
+	if accessToken == nil || !accessToken.IsImpersonation() {
+		return ErrNotImpersonating
+	}
+
+	return s.tokens.Revoke(ctx, accessToken)
+}
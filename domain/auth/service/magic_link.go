@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-api/config"
+	"go-api/domain/auth/entity"
+	"go-api/repository"
+	"go-api/shared/logger"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidMagicLinkToken = errors.New("invalid or expired login link")
+
+// SendMagicLink emails a one-time login token to the user.
+// It does not report whether the email exists to avoid account enumeration.
+func (s *AuthService) SendMagicLink(ctx context.Context, email string) error {
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if loginableUser(user, ErrUserNotFound) != nil {
+		return nil
+	}
+
+	magicLinkRepo := repository.NewMagicLinkTokenRepository(s.provider.DB)
+
+	// Only the latest link should be usable
+	if err := magicLinkRepo.RevokeAllUserTokens(ctx, user.ID); err != nil {
+		return err
+	}
+
+	expiry := config.Get().MagicLinkExpiry
+	token, _, err := magicLinkRepo.Create(ctx, user.ID, expiry)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := s.provider.Email.SendMagicLinkEmail(user.Email, user.Name, token, int(expiry.Minutes())); err != nil {
+			logger.Errorf("Failed to send magic link email: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// ConsumeMagicLink exchanges a magic link token for a regular session.
+// Users with two-factor authentication still have to complete the challenge.
+func (s *AuthService) ConsumeMagicLink(ctx context.Context, token string) (*entity.LoginResult, error) {
+	loginToken, err := repository.NewMagicLinkTokenRepository(s.provider.DB).FindByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidMagicLinkToken
+		}
+		return nil, err
+	}
+
+	if !loginToken.IsValid() {
+		return nil, ErrInvalidMagicLinkToken
+	}
+
+	user := &loginToken.User
+	if err := loginableUser(user, ErrInvalidMagicLinkToken); err != nil {
+		return nil, err
+	}
+
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewMagicLinkTokenRepository(tx).MarkUsed(ctx, loginToken.ID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidMagicLinkToken
+			}
+			return err
+		}
+
+		// Receiving the link proves ownership of the mailbox
+		if !user.IsEmailVerified() {
+			return repository.NewUserRepository(tx).MarkEmailVerified(ctx, user.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, user)
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-api/config"
+	"go-api/domain/auth/entity"
+	"go-api/model"
+	"go-api/oidc"
+	"go-api/repository"
+	"go-api/shared/constant"
+	"go-api/shared/logger"
+	"go-api/shared/timezone"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownOIDCProvider   = errors.New("unknown login provider")
+	ErrInvalidOIDCState      = errors.New("invalid or expired login state")
+	ErrOIDCLoginFailed       = errors.New("login with the provider failed")
+	ErrOIDCEmailMissing      = errors.New("the provider did not return a verified email address")
+	ErrOIDCEmailConflict     = errors.New("an account with this email already exists, sign in and link the provider from your account")
+	ErrIdentityAlreadyLinked = errors.New("this external account is already linked to a user")
+	ErrIdentityNotFound      = errors.New("linked identity not found")
+	ErrLastLoginMethod       = errors.New("cannot unlink the only way to sign in, set a password first")
+)
+
+// ListOIDCProviders returns the configured login providers
+func (s *AuthService) ListOIDCProviders() []entity.OIDCProviderResponse {
+	providers := s.provider.OIDC.List()
+	result := make([]entity.OIDCProviderResponse, 0, len(providers))
+	for _, provider := range providers {
+		result = append(result, entity.OIDCProviderResponse{
+			Name:        provider.Name(),
+			DisplayName: provider.DisplayName(),
+		})
+	}
+	return result
+}
+
+// StartOIDCLogin stores a new state, nonce and PKCE verifier and returns the provider
+// authorization URL. With linkUserID set the callback links the identity instead of signing in.
+func (s *AuthService) StartOIDCLogin(ctx context.Context, providerName string, linkUserID *uint) (string, error) {
+	provider, err := s.provider.OIDC.Get(providerName)
+	if err != nil {
+		return "", ErrUnknownOIDCProvider
+	}
+
+	codeVerifier := oidc.GenerateCodeVerifier()
+	state, loginState, err := repository.NewOIDCLoginStateRepository(s.provider.DB).Create(ctx, providerName, codeVerifier, linkUserID, config.Get().OIDCStateExpiry)
+	if err != nil {
+		return "", err
+	}
+
+	return provider.AuthCodeURL(ctx, state, loginState.Nonce, codeVerifier)
+}
+
+// CompleteOIDCLogin handles the provider callback: it checks the state, redeems the code,
+// verifies the ID token and then signs the user in or links the identity. Linking states are
+// bound to the user who started the flow and only complete when userID is that same user, so
+// nobody can link their own external account to a victim by sending them the authorization URL
+// and vice versa. Login states only complete without a userID.
+func (s *AuthService) CompleteOIDCLogin(ctx context.Context, providerName, code, state string, userID *uint) (*entity.OIDCCallbackResult, error) {
+	provider, err := s.provider.OIDC.Get(providerName)
+	if err != nil {
+		return nil, ErrUnknownOIDCProvider
+	}
+
+	stateRepo := repository.NewOIDCLoginStateRepository(s.provider.DB)
+	loginState, err := stateRepo.FindByState(ctx, state)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidOIDCState
+		}
+		return nil, err
+	}
+	if !loginState.IsValid() || loginState.Provider != providerName {
+		return nil, ErrInvalidOIDCState
+	}
+	if !sameUser(loginState.UserID, userID) {
+		logger.Warnf("OpenID Connect callback for %s completed by a different user than the one who started it", providerName)
+		return nil, ErrInvalidOIDCState
+	}
+
+	// Consume the state before talking to the provider so a callback cannot be replayed
+	if err := stateRepo.MarkUsed(ctx, loginState.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidOIDCState
+		}
+		return nil, err
+	}
+
+	identity, err := provider.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		logger.Warnf("OpenID Connect login with %s failed: %v", providerName, err)
+		return nil, ErrOIDCLoginFailed
+	}
+
+	if loginState.UserID != nil {
+		linked, err := s.linkIdentity(ctx, *loginState.UserID, identity)
+		if err != nil {
+			return nil, err
+		}
+		return &entity.OIDCCallbackResult{Identity: linked}, nil
+	}
+
+	user, err := s.findOrCreateOIDCUser(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loginableUser(user, ErrOIDCLoginFailed); err != nil {
+		return nil, err
+	}
+
+	login, err := s.completeLogin(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.OIDCCallbackResult{Login: login}, nil
+}
+
+// sameUser reports whether both IDs are unset or refer to the same user
+func sameUser(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// linkIdentity attaches an external account to an existing user
+func (s *AuthService) linkIdentity(ctx context.Context, userID uint, identity *oidc.Identity) (*model.UserIdentity, error) {
+	identityRepo := repository.NewUserIdentityRepository(s.provider.DB)
+
+	existing, err := identityRepo.FindByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		if existing.UserID != userID {
+			return nil, ErrIdentityAlreadyLinked
+		}
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	linked := &model.UserIdentity{
+		UserID:   userID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+	if err := identityRepo.Create(ctx, linked); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("User %d linked %s identity", userID, identity.Provider)
+
+	return linked, nil
+}
+
+// findOrCreateOIDCUser resolves the local user of an external account. Unknown accounts are
+// linked to the user with the same verified email, or a new user is registered.
+func (s *AuthService) findOrCreateOIDCUser(ctx context.Context, identity *oidc.Identity) (*model.User, error) {
+	identityRepo := repository.NewUserIdentityRepository(s.provider.DB)
+
+	existing, err := identityRepo.FindByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		if isDeletedUser(&existing.User) {
+			return nil, ErrOIDCLoginFailed
+		}
+		if err := identityRepo.TouchLogin(ctx, existing.ID, identity.Email); err != nil {
+			logger.Warnf("Failed to record login for identity %d: %v", existing.ID, err)
+		}
+		return &existing.User, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, ErrOIDCEmailMissing
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, identity.Email)
+	if err == nil {
+		// Only link automatically when both sides proved ownership of the address,
+		// otherwise whoever registered the email first could take over the account
+		if !user.IsEmailVerified() {
+			return nil, ErrOIDCEmailConflict
+		}
+		if _, err := s.linkIdentity(ctx, user.ID, identity); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return s.registerOIDCUser(ctx, identity)
+}
+
+// registerOIDCUser creates a user without password for an external account
+func (s *AuthService) registerOIDCUser(ctx context.Context, identity *oidc.Identity) (*model.User, error) {
+	roleUser, err := s.roleRepo.FindByCode(ctx, constant.RoleCodeUser)
+	if err != nil {
+		return nil, err
+	}
+
+	name := identity.Name
+	if name == "" {
+		name = strings.Split(identity.Email, "@")[0]
+	}
+
+	now := timezone.Now()
+	user := &model.User{
+		Name:            name,
+		Email:           identity.Email,
+		RoleID:          roleUser.ID,
+		EmailVerifiedAt: &now,
+	}
+
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewUserRepository(tx).Create(ctx, user); err != nil {
+			return err
+		}
+
+		return repository.NewUserIdentityRepository(tx).Create(ctx, &model.UserIdentity{
+			UserID:      user.ID,
+			Provider:    identity.Provider,
+			Subject:     identity.Subject,
+			Email:       identity.Email,
+			LastLoginAt: &now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user.Role = *roleUser
+
+	go func() {
+		if err := s.provider.Email.SendWelcomeEmail(user.Email, user.Name); err != nil {
+			logger.Errorf("Failed to send email: %v", err)
+		}
+	}()
+
+	return user, nil
+}
+
+// ListIdentities returns the external accounts linked to a user
+func (s *AuthService) ListIdentities(ctx context.Context, userID uint) ([]model.UserIdentity, error) {
+	return repository.NewUserIdentityRepository(s.provider.DB).FindByUser(ctx, userID)
+}
+
+// UnlinkIdentity removes a linked external account, unless it is the user's only way to sign in
+func (s *AuthService) UnlinkIdentity(ctx context.Context, userID, id uint) error {
+	identityRepo := repository.NewUserIdentityRepository(s.provider.DB)
+
+	identity, err := identityRepo.FindByIDForUser(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrIdentityNotFound
+		}
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Password == "" {
+		count, err := identityRepo.CountByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if count <= 1 {
+			return ErrLastLoginMethod
+		}
+	}
+
+	return identityRepo.Delete(ctx, identity.ID)
+}
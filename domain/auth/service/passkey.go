@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api/config"
+	"go-api/domain/auth/entity"
+	"go-api/model"
+	"go-api/repository"
+	"go-api/shared/logger"
+	"strconv"
+	"strings"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPasskeysDisabled          = errors.New("passkey login is not enabled")
+	ErrInvalidPasskeyChallenge   = errors.New("invalid or expired passkey challenge")
+	ErrPasskeyVerificationFailed = errors.New("passkey verification failed")
+	ErrPasskeyAlreadyRegistered  = errors.New("passkey is already registered")
+	ErrPasskeyNotFound           = errors.New("passkey not found")
+)
+
+// webAuthnUser adapts a user and their passkeys to the interface of the WebAuthn library
+type webAuthnUser struct {
+	user        *model.User
+	credentials []model.WebAuthnCredential
+}
+
+// WebAuthnID is the user handle stored in the passkey, it identifies the user on login
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return []byte(strconv.FormatUint(uint64(u.user.ID), 10))
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	if u.user.Name != "" {
+		return u.user.Name
+	}
+	return u.user.Email
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.credentials))
+	for _, credential := range u.credentials {
+		transports := make([]protocol.AuthenticatorTransport, 0)
+		for _, transport := range credential.TransportList() {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+
+		credentials = append(credentials, webauthn.Credential{
+			ID:              credential.CredentialID,
+			PublicKey:       credential.PublicKey,
+			AttestationType: credential.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: credential.BackupEligible,
+				BackupState:    credential.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    credential.AAGUID,
+				SignCount: credential.SignCount,
+			},
+		})
+	}
+	return credentials
+}
+
+// BeginPasskeyRegistration starts the registration of a passkey for a signed in user.
+// The returned options are passed to navigator.credentials.create() by the client.
+func (s *AuthService) BeginPasskeyRegistration(ctx context.Context, userID uint) (*protocol.CredentialCreation, error) {
+	if s.provider.WebAuthn == nil {
+		return nil, ErrPasskeysDisabled
+	}
+
+	waUser, err := s.loadWebAuthnUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Passkeys must be discoverable so the user can sign in without typing an email
+	creation, session, err := s.provider.WebAuthn.BeginRegistration(waUser,
+		webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			UserVerification:   protocol.VerificationRequired,
+		}),
+		webauthn.WithExclusions(webauthn.Credentials(waUser.WebAuthnCredentials()).CredentialDescriptors()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.saveWebAuthnSession(ctx, model.WebAuthnCeremonyRegistration, session, &userID); err != nil {
+		return nil, err
+	}
+
+	return creation, nil
+}
+
+// FinishPasskeyRegistration verifies the authenticator's answer and stores the new passkey
+func (s *AuthService) FinishPasskeyRegistration(ctx context.Context, userID uint, name string, response []byte) (*model.WebAuthnCredential, error) {
+	if s.provider.WebAuthn == nil {
+		return nil, ErrPasskeysDisabled
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPasskeyVerificationFailed, err)
+	}
+
+	challenge, session, err := s.consumeWebAuthnSession(ctx, model.WebAuthnCeremonyRegistration, parsed.Response.CollectedClientData.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.UserID == nil || *challenge.UserID != userID {
+		return nil, ErrInvalidPasskeyChallenge
+	}
+
+	waUser, err := s.loadWebAuthnUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := s.provider.WebAuthn.CreateCredential(waUser, *session, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPasskeyVerificationFailed, err)
+	}
+
+	credentialRepo := repository.NewWebAuthnCredentialRepository(s.provider.DB)
+	exists, err := credentialRepo.CredentialIDExists(ctx, credential.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrPasskeyAlreadyRegistered
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Passkey"
+	}
+
+	webAuthnCredential := &model.WebAuthnCredential{
+		UserID:          userID,
+		Name:            name,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		Transports:      strings.Join(transports, " "),
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+	}
+	if err := credentialRepo.Create(ctx, webAuthnCredential); err != nil {
+		return nil, err
+	}
+
+	return webAuthnCredential, nil
+}
+
+// BeginPasskeyLogin starts a login with a discoverable passkey. The authenticator lets the
+// user pick one of their passkeys, so no email is needed.
+func (s *AuthService) BeginPasskeyLogin(ctx context.Context) (*protocol.CredentialAssertion, error) {
+	if s.provider.WebAuthn == nil {
+		return nil, ErrPasskeysDisabled
+	}
+
+	assertion, session, err := s.provider.WebAuthn.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.saveWebAuthnSession(ctx, model.WebAuthnCeremonyLogin, session, nil); err != nil {
+		return nil, err
+	}
+
+	return assertion, nil
+}
+
+// FinishPasskeyLogin verifies the signed challenge and starts a session. A passkey with user
+// verification already combines possession and a PIN or biometric, so two-factor
+// authentication is not asked for again.
+func (s *AuthService) FinishPasskeyLogin(ctx context.Context, response []byte) (*entity.LoginResult, error) {
+	if s.provider.WebAuthn == nil {
+		return nil, ErrPasskeysDisabled
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPasskeyVerificationFailed, err)
+	}
+
+	_, session, err := s.consumeWebAuthnSession(ctx, model.WebAuthnCeremonyLogin, parsed.Response.CollectedClientData.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	credentialRepo := repository.NewWebAuthnCredentialRepository(s.provider.DB)
+
+	var stored *model.WebAuthnCredential
+	findUser := func(rawID, userHandle []byte) (webauthn.User, error) {
+		credential, err := credentialRepo.FindByCredentialID(ctx, rawID)
+		if err != nil {
+			return nil, err
+		}
+		if isDeletedUser(&credential.User) {
+			return nil, ErrUserNotFound
+		}
+		stored = credential
+		return &webAuthnUser{user: &credential.User, credentials: []model.WebAuthnCredential{*credential}}, nil
+	}
+
+	// The library checks that the user handle in the response belongs to the passkey owner
+	credential, err := s.provider.WebAuthn.ValidateDiscoverableLogin(findUser, *session, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPasskeyVerificationFailed, err)
+	}
+
+	// A signature counter going backwards means the authenticator may have been cloned
+	if credential.Authenticator.CloneWarning {
+		logger.Warnf("Passkey %d of user %d reported a stale signature counter, possible cloned authenticator", stored.ID, stored.UserID)
+		return nil, ErrPasskeyVerificationFailed
+	}
+
+	user := stored.User
+	if err := loginableUser(&user, ErrPasskeyVerificationFailed); err != nil {
+		return nil, err
+	}
+
+	if err := credentialRepo.RecordLogin(ctx, stored.ID, credential.Authenticator.SignCount, credential.Flags.BackupState); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.startSession(ctx, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.LoginResult{Tokens: tokens}, nil
+}
+
+// ListPasskeys returns the passkeys of a user
+func (s *AuthService) ListPasskeys(ctx context.Context, userID uint) ([]model.WebAuthnCredential, error) {
+	return repository.NewWebAuthnCredentialRepository(s.provider.DB).FindByUser(ctx, userID)
+}
+
+func (s *AuthService) RenamePasskey(ctx context.Context, userID, id uint, name string) (*model.WebAuthnCredential, error) {
+	credentialRepo := repository.NewWebAuthnCredentialRepository(s.provider.DB)
+
+	credential, err := credentialRepo.FindByIDForUser(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPasskeyNotFound
+		}
+		return nil, err
+	}
+
+	credential.Name = strings.TrimSpace(name)
+	if err := credentialRepo.UpdateName(ctx, credential.ID, credential.Name); err != nil {
+		return nil, err
+	}
+
+	return credential, nil
+}
+
+func (s *AuthService) DeletePasskey(ctx context.Context, userID, id uint) error {
+	if err := repository.NewWebAuthnCredentialRepository(s.provider.DB).Delete(ctx, id, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPasskeyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *AuthService) loadWebAuthnUser(ctx context.Context, userID uint) (*webAuthnUser, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := repository.NewWebAuthnCredentialRepository(s.provider.DB).FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webAuthnUser{user: user, credentials: credentials}, nil
+}
+
+// saveWebAuthnSession stores the session data of a ceremony until the client answers it
+func (s *AuthService) saveWebAuthnSession(ctx context.Context, ceremony string, session *webauthn.SessionData, userID *uint) error {
+	sessionData, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	return repository.NewWebAuthnChallengeRepository(s.provider.DB).Create(ctx, ceremony, session.Challenge, string(sessionData), userID, config.Get().WebAuthnChallengeExpiry)
+}
+
+// consumeWebAuthnSession finds the ceremony a response answers and marks it as used,
+// so each challenge can only be answered once
+func (s *AuthService) consumeWebAuthnSession(ctx context.Context, ceremony, challenge string) (*model.WebAuthnChallenge, *webauthn.SessionData, error) {
+	challengeRepo := repository.NewWebAuthnChallengeRepository(s.provider.DB)
+
+	webAuthnChallenge, err := challengeRepo.FindByChallenge(ctx, ceremony, challenge)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidPasskeyChallenge
+		}
+		return nil, nil, err
+	}
+
+	if !webAuthnChallenge.IsValid() {
+		return nil, nil, ErrInvalidPasskeyChallenge
+	}
+
+	if err := challengeRepo.MarkUsed(ctx, webAuthnChallenge.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidPasskeyChallenge
+		}
+		return nil, nil, err
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal([]byte(webAuthnChallenge.SessionData), &session); err != nil {
+		return nil, nil, err
+	}
+
+	return webAuthnChallenge, &session, nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-api/model"
+	"go-api/repository"
+	"go-api/shared/validator"
+
+	"gorm.io/gorm"
+)
+
+// PasswordPolicyError lists every rule of the password policy a new password breaks
+type PasswordPolicyError struct {
+	Messages []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password does not meet the password policy"
+}
+
+// checkNewPassword runs the password policy against a new password. The user is nil on
+// registration, otherwise the password is also compared against the recent password history.
+func (s *AuthService) checkNewPassword(ctx context.Context, user *model.User, email, name, password string) error {
+	var messages []string
+	if details := validator.ValidatePasswordWithDetails(password, email, name); details != nil {
+		messages = details["password"]
+	}
+
+	if user != nil && s.historySize > 0 {
+		reused, err := s.isRecentPassword(ctx, user, password)
+		if err != nil {
+			return err
+		}
+		if reused {
+			messages = append(messages, fmt.Sprintf("Cannot reuse any of your last %d passwords", s.historySize))
+		}
+	}
+
+	if len(messages) > 0 {
+		return &PasswordPolicyError{Messages: messages}
+	}
+	return nil
+}
+
+// isRecentPassword checks the password against the current hash and the latest history entries
+func (s *AuthService) isRecentPassword(ctx context.Context, user *model.User, password string) (bool, error) {
+	if user.Password != "" && s.passwords.Verify(user.Password, password) == nil {
+		return true, nil
+	}
+
+	history, err := s.historyRepo.FindRecent(ctx, user.ID, s.historySize)
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry := range history {
+		if s.passwords.Verify(entry.PasswordHash, password) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// recordPasswordHistory stores the new hash and drops entries beyond the configured history size
+func (s *AuthService) recordPasswordHistory(ctx context.Context, tx *gorm.DB, userID uint, hashedPassword string) error {
+	if s.historySize <= 0 {
+		return nil
+	}
+
+	historyRepo := repository.NewPasswordHistoryRepository(tx)
+	if err := historyRepo.Create(ctx, userID, hashedPassword); err != nil {
+		return err
+	}
+
+	return historyRepo.Prune(ctx, userID, s.historySize)
+}
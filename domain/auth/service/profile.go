@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-api/domain/auth/entity"
+	"go-api/model"
+	"go-api/repository"
+	"go-api/shared/timezone"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var ErrPasswordUnchanged = errors.New("new password must be different from the current password")
+
+// GetProfile returns the current state of a user from the database
+func (s *AuthService) GetProfile(ctx context.Context, userID uint) (*model.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *AuthService) UpdateProfile(ctx context.Context, userID uint, req *entity.UpdateProfileRequest) (*model.User, error) {
+	if err := s.userRepo.UpdateName(ctx, userID, strings.TrimSpace(req.Name)); err != nil {
+		return nil, err
+	}
+	return s.GetProfile(ctx, userID)
+}
+
+// ChangePassword replaces the password after checking the current one. Every session of the
+// user is revoked and a fresh token pair is returned so only the caller stays signed in.
+func (s *AuthService) ChangePassword(ctx context.Context, userID uint, currentPassword, newPassword string) (*entity.TokenPair, error) {
+	user, err := s.verifyPassword(ctx, userID, currentPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	if currentPassword == newPassword {
+		return nil, ErrPasswordUnchanged
+	}
+
+	if err := s.checkNewPassword(ctx, user, user.Email, user.Name, newPassword); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := s.passwords.Hash(newPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	familyID, err := s.refreshRepo.NewFamilyID()
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens *entity.TokenPair
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewUserRepository(tx).UpdatePassword(ctx, userID, hashedPassword); err != nil {
+			return err
+		}
+
+		if err := s.recordPasswordHistory(ctx, tx, userID, hashedPassword); err != nil {
+			return err
+		}
+
+		if err := s.revokeAllUserSessions(ctx, tx, userID); err != nil {
+			return err
+		}
+
+		tokens, err = s.issueTokenPair(ctx, tx, user, familyID, nil, timezone.Now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
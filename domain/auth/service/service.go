@@ -4,23 +4,48 @@ import (
 	"context"
 	"errors"
 	"go-api/app"
+	"go-api/authtoken"
 	"go-api/config"
 	entity "go-api/domain/auth/entity"
 	"go-api/model"
+	"go-api/passwordhash"
 	"go-api/repository"
 	"go-api/shared/constant"
 	"go-api/shared/logger"
+	"go-api/shared/timezone"
 	"time"
 
-	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidResetToken        = errors.New("invalid or expired reset token")
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrVerificationThrottled    = errors.New("verification email was sent recently, please try again later")
+	ErrInvalidRefreshToken      = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused       = errors.New("refresh token has already been used")
+	ErrInvalidPassword          = errors.New("invalid password")
+	ErrInvalidCredentials       = errors.New("invalid email or password")
+	ErrSessionExpired           = errors.New("session has expired, please sign in again")
 )
 
 type AuthService struct {
 	provider 			*app.Provider
 	userRepo        *repository.UserRepository
 	roleRepo        *repository.RoleRepository
-	accessTokenRepo *repository.AccessTokenRepository
+	tokens          authtoken.Strategy
+	passwords       *passwordhash.Hasher
+	resetTokenRepo  *repository.PasswordResetTokenRepository
+	verifyTokenRepo *repository.EmailVerificationTokenRepository
+	refreshRepo     *repository.RefreshTokenRepository
+	historyRepo     *repository.PasswordHistoryRepository
+	historySize     int
 	tokenExpiry     time.Duration
+	refreshExpiry   time.Duration
+	resetExpiry     time.Duration
+	verifyExpiry    time.Duration
+	verifyInterval  time.Duration
 }
 
 func NewAuthService(p *app.Provider) *AuthService {
@@ -28,53 +53,335 @@ func NewAuthService(p *app.Provider) *AuthService {
 		provider: 			 p,
 		userRepo:        repository.NewUserRepository(p.DB),
 		roleRepo:        repository.NewRoleRepository(p.DB),
-		accessTokenRepo: repository.NewAccessTokenRepository(p.DB),
+		tokens:          p.Tokens,
+		passwords:       p.Passwords,
+		resetTokenRepo:  repository.NewPasswordResetTokenRepository(p.DB),
+		verifyTokenRepo: repository.NewEmailVerificationTokenRepository(p.DB),
+		refreshRepo:     repository.NewRefreshTokenRepository(p.DB),
+		historyRepo:     repository.NewPasswordHistoryRepository(p.DB),
+		historySize:     config.Get().PasswordHistorySize,
 		tokenExpiry:     config.Get().JWTExpiry,
+		refreshExpiry:   config.Get().RefreshTokenExpiry,
+		resetExpiry:     config.Get().PasswordResetExpiry,
+		verifyExpiry:    config.Get().EmailVerificationExpiry,
+		verifyInterval:  config.Get().EmailVerificationResendInterval,
 	}
 }
 
-func (s *AuthService) Login(ctx context.Context, email, password string) (*model.AccessToken, error) {
+// Login checks the email and password. Unknown emails, wrong passwords and locked accounts all
+// fail with ErrInvalidCredentials so the response does not reveal which accounts exist or are
+// locked; the owner of a locked account is told by email.
+func (s *AuthService) Login(ctx context.Context, email, password string) (*entity.LoginResult, error) {
 	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidCredentials
+		}
 		return nil, err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return nil, errors.New("invalid credentials")
+	// A locked account is rejected before the password is checked so guesses do not count
+	if user.IsLocked() {
+		logger.Warnf("Login attempt for locked account %d", user.ID)
+		return nil, ErrInvalidCredentials
 	}
 
-	// Create access token
-	accessToken, err := s.accessTokenRepo.Create(ctx, user.ID, s.tokenExpiry)
+	if err := s.checkPassword(ctx, user, password); err != nil {
+		if lockErr := s.recordFailedLogin(ctx, user); lockErr != nil && !errors.Is(lockErr, ErrAccountLocked) {
+			logger.Errorf("Failed to record failed login for user %d: %v", user.ID, lockErr)
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.completeLogin(ctx, user)
+}
+
+// completeLogin finishes a login once the first factor was verified
+func (s *AuthService) completeLogin(ctx context.Context, user *model.User) (*entity.LoginResult, error) {
+	// Users with two-factor authentication get a challenge instead of tokens. Failed logins
+	// are only reset once the second factor is verified as well.
+	enabled, err := s.isTwoFactorEnabled(ctx, user.ID)
 	if err != nil {
 		return nil, err
 	}
+	if enabled {
+		return s.startTwoFactorChallenge(ctx, user.ID)
+	}
 
-	return accessToken, nil
+	if err := s.clearFailedLogins(ctx, user); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.startSession(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.LoginResult{Tokens: tokens}, nil
 }
 
-func (s *AuthService) ValidateToken(ctx context.Context, token string) (*model.AccessToken, error) {
-	accessToken, err := s.accessTokenRepo.FindByToken(ctx, token)
+// startSession starts a new refresh token family and issues its first token pair
+func (s *AuthService) startSession(ctx context.Context, user *model.User) (*entity.TokenPair, error) {
+	// Signing in during the grace period keeps the account
+	if err := s.cancelAccountDeletion(ctx, user); err != nil {
+		return nil, err
+	}
+
+	familyID, err := s.refreshRepo.NewFamilyID()
 	if err != nil {
 		return nil, err
 	}
 
-	if !accessToken.IsValid() {
-		return nil, errors.New("invalid or expired token")
+	return s.issueTokenPair(ctx, s.provider.DB, user, familyID, nil, timezone.Now())
+}
+
+// verifyPassword loads a user and checks the given password against the stored hash.
+// Wrong passwords count toward the lockout like failed logins, so a stolen access token
+// cannot be used to guess the password.
+func (s *AuthService) verifyPassword(ctx context.Context, userID uint, password string) (*model.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	return accessToken, nil
+	if user.IsLocked() {
+		return nil, ErrAccountLocked
+	}
+
+	if err := s.checkPassword(ctx, user, password); err != nil {
+		if lockErr := s.recordFailedLogin(ctx, user); lockErr != nil {
+			if errors.Is(lockErr, ErrAccountLocked) {
+				return nil, lockErr
+			}
+			logger.Errorf("Failed to record failed password check for user %d: %v", user.ID, lockErr)
+		}
+		return nil, ErrInvalidPassword
+	}
+
+	if err := s.clearFailedLogins(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// checkPassword verifies a password against the user's stored hash. After a successful check
+// a hash using an outdated algorithm or parameters is replaced, which is the only moment the
+// plain password is available to upgrade it.
+func (s *AuthService) checkPassword(ctx context.Context, user *model.User, password string) error {
+	if err := s.passwords.Verify(user.Password, password); err != nil {
+		return err
+	}
+
+	if s.passwords.NeedsRehash(user.Password) {
+		hashedPassword, err := s.passwords.Hash(password)
+		if err == nil {
+			err = s.userRepo.UpdatePassword(ctx, user.ID, hashedPassword)
+		}
+		if err != nil {
+			// The login itself succeeded, the upgrade is retried on the next one
+			logger.Warnf("Failed to upgrade password hash for user %d: %v", user.ID, err)
+			return nil
+		}
+		user.Password = hashedPassword
+	}
+
+	return nil
+}
+
+// issueTokenPair creates a short-lived access token and a refresh token in the given family.
+// Neither token outlives the absolute lifetime of the session started at sessionStartedAt.
+func (s *AuthService) issueTokenPair(ctx context.Context, db *gorm.DB, user *model.User, familyID string, parentID *uint, sessionStartedAt time.Time) (*entity.TokenPair, error) {
+	accessExpiry, refreshExpiry := s.tokenExpiry, s.refreshExpiry
+	if lifetime := config.Get().TokenAbsoluteLifetime; lifetime > 0 {
+		remaining := sessionStartedAt.Add(lifetime).Sub(timezone.Now())
+		if remaining <= 0 {
+			return nil, ErrSessionExpired
+		}
+		accessExpiry = min(accessExpiry, remaining)
+		refreshExpiry = min(refreshExpiry, remaining)
+	}
+
+	var pair *entity.TokenPair
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		accessToken, err := s.tokens.WithDB(tx).Issue(ctx, user, familyID, accessExpiry)
+		if err != nil {
+			return err
+		}
+
+		// Stateless access tokens have no row to link to
+		var accessTokenID *uint
+		if accessToken.ID != 0 {
+			accessTokenID = &accessToken.ID
+		}
+
+		refreshToken, refreshModel, err := repository.NewRefreshTokenRepository(tx).Create(ctx, user.ID, familyID, parentID, accessTokenID, sessionStartedAt, refreshExpiry)
+		if err != nil {
+			return err
+		}
+
+		pair = &entity.TokenPair{
+			AccessToken:      accessToken,
+			RefreshToken:     refreshToken,
+			RefreshExpiresAt: refreshModel.ExpiresAt,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair, rotating the refresh token.
+// Presenting a token that was already exchanged revokes the whole family.
+func (s *AuthService) Refresh(ctx context.Context, token string) (*entity.TokenPair, error) {
+	refreshToken, err := s.refreshRepo.FindByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	if refreshToken.IsUsed() && refreshToken.RevokedAt == nil {
+		logger.Warnf("Refresh token reuse detected for user %d, revoking family %s", refreshToken.UserID, refreshToken.FamilyID)
+		if err := s.revokeRefreshFamily(ctx, refreshToken.UserID, refreshToken.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	if !refreshToken.IsValid() {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if err := s.checkSessionActivity(ctx, refreshToken); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, refreshToken.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	var pair *entity.TokenPair
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewRefreshTokenRepository(tx).MarkUsed(ctx, refreshToken.ID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRefreshTokenReused
+			}
+			return err
+		}
+
+		// The access token issued with the previous refresh token is superseded.
+		// Stateless access tokens cannot be looked up and simply run out.
+		if refreshToken.AccessTokenID != nil {
+			if err := repository.NewAccessTokenRepository(tx).RevokeByIDs(ctx, []uint{*refreshToken.AccessTokenID}); err != nil {
+				return err
+			}
+		}
+
+		pair, err = s.issueTokenPair(ctx, tx, user, refreshToken.FamilyID, &refreshToken.ID, refreshToken.SessionStartedAt)
+		return err
+	})
+	if err != nil {
+		// A concurrent exchange of the same token is treated as reuse as well
+		if errors.Is(err, ErrRefreshTokenReused) {
+			logger.Warnf("Concurrent refresh token use detected for user %d, revoking family %s", refreshToken.UserID, refreshToken.FamilyID)
+			if revokeErr := s.revokeRefreshFamily(ctx, refreshToken.UserID, refreshToken.FamilyID); revokeErr != nil {
+				return nil, revokeErr
+			}
+		}
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+// checkSessionActivity ends a session that was idle for longer than the idle timeout. The last
+// activity is the latest use of the session's access token, or the last refresh for stateless tokens.
+func (s *AuthService) checkSessionActivity(ctx context.Context, refreshToken *model.RefreshToken) error {
+	idleTimeout := config.Get().TokenIdleTimeout
+	if idleTimeout <= 0 {
+		return nil
+	}
+
+	lastActivity := refreshToken.CreatedAt
+	if refreshToken.AccessTokenID != nil {
+		accessToken, err := repository.NewAccessTokenRepository(s.provider.DB).FindByID(ctx, *refreshToken.AccessTokenID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err == nil && accessToken.LastUsedAt != nil && accessToken.LastUsedAt.After(lastActivity) {
+			lastActivity = *accessToken.LastUsedAt
+		}
+	}
+
+	if timezone.Now().Before(lastActivity.Add(idleTimeout)) {
+		return nil
+	}
+
+	if err := s.revokeRefreshFamily(ctx, refreshToken.UserID, refreshToken.FamilyID); err != nil {
+		return err
+	}
+	return ErrSessionExpired
+}
+
+// revokeRefreshFamily revokes every refresh token of a family and the access tokens issued with them
+func (s *AuthService) revokeRefreshFamily(ctx context.Context, userID uint, familyID string) error {
+	return s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.tokens.WithDB(tx).RevokeSession(ctx, userID, familyID); err != nil {
+			return err
+		}
+
+		return repository.NewRefreshTokenRepository(tx).RevokeFamily(ctx, familyID)
+	})
+}
+
+// ValidateToken checks an access token with the configured token strategy
+func (s *AuthService) ValidateToken(ctx context.Context, token string) (*model.AccessToken, error) {
+	return s.tokens.Validate(ctx, token)
 }
 
 func (s *AuthService) Logout(ctx context.Context, token string) error {
-	return s.accessTokenRepo.RevokeToken(ctx, token)
+	accessToken, err := s.tokens.Validate(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	// Also end the refresh token family so the session cannot be resumed
+	if accessToken.SessionID != "" {
+		return s.revokeRefreshFamily(ctx, accessToken.UserID, accessToken.SessionID)
+	}
+
+	return s.tokens.Revoke(ctx, accessToken)
+}
+
+// revokeAllUserSessions revokes every access and refresh token of a user using db
+func (s *AuthService) revokeAllUserSessions(ctx context.Context, db *gorm.DB, userID uint) error {
+	if err := repository.NewRefreshTokenRepository(db).RevokeAllUserTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.tokens.WithDB(db).RevokeAllUserTokens(ctx, userID)
 }
 
 func (s *AuthService) LogoutAll(ctx context.Context, userID uint) error {
-	return s.accessTokenRepo.RevokeAllUserTokens(ctx, userID)
+	return s.revokeAllUserSessions(ctx, s.provider.DB, userID)
 }
 
 func (s *AuthService) Register(ctx context.Context, req *entity.RegisterRequest) error {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err := s.checkNewPassword(ctx, nil, req.Email, req.Name, req.Password); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.passwords.Hash(req.Password)
 	if err != nil {
 		return err
 	}
@@ -89,7 +396,17 @@ func (s *AuthService) Register(ctx context.Context, req *entity.RegisterRequest)
 		Name:     req.Name,
 		Email:    req.Email,
 		RoleID:   roleUser.ID,
-		Password: string(hashedPassword),
+		Password: hashedPassword,
+	}
+
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewUserRepository(tx).Create(ctx, user); err != nil {
+			return err
+		}
+		return s.recordPasswordHistory(ctx, tx, user.ID, hashedPassword)
+	})
+	if err != nil {
+		return err
 	}
 
 	go func() {
@@ -98,5 +415,161 @@ func (s *AuthService) Register(ctx context.Context, req *entity.RegisterRequest)
 		}
 	}()
 
-	return s.userRepo.Create(ctx, user)
+	// Registration succeeds even if the verification email fails, the user can request a new one
+	if err := s.sendVerificationEmail(ctx, user); err != nil {
+		logger.Errorf("Failed to issue email verification for user %d: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// ForgotPassword issues a password reset token and emails it to the user.
+// It does not report whether the email exists to avoid account enumeration.
+func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	// Only the latest reset token should be usable
+	if err := s.resetTokenRepo.RevokeAllUserTokens(ctx, user.ID); err != nil {
+		return err
+	}
+
+	token, _, err := s.resetTokenRepo.Create(ctx, user.ID, s.resetExpiry)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := s.provider.Email.SendPasswordResetEmail(user.Email, user.Name, token, int(s.resetExpiry.Minutes())); err != nil {
+			logger.Errorf("Failed to send password reset email: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// ResetPassword consumes a reset token, sets the new password and revokes all sessions of the user
+func (s *AuthService) ResetPassword(ctx context.Context, token, password string) error {
+	resetToken, err := s.resetTokenRepo.FindByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	if !resetToken.IsValid() || isDeletedUser(&resetToken.User) {
+		return ErrInvalidResetToken
+	}
+
+	if err := s.checkNewPassword(ctx, &resetToken.User, resetToken.User.Email, resetToken.User.Name, password); err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.passwords.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	return s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		resetTokenRepo := repository.NewPasswordResetTokenRepository(tx)
+		if err := resetTokenRepo.MarkUsed(ctx, resetToken.ID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidResetToken
+			}
+			return err
+		}
+
+		if err := repository.NewUserRepository(tx).UpdatePassword(ctx, resetToken.UserID, hashedPassword); err != nil {
+			return err
+		}
+
+		if err := s.recordPasswordHistory(ctx, tx, resetToken.UserID, hashedPassword); err != nil {
+			return err
+		}
+
+		if err := resetTokenRepo.RevokeAllUserTokens(ctx, resetToken.UserID); err != nil {
+			return err
+		}
+
+		// Proving ownership of the mailbox also lifts a lockout
+		if err := repository.NewUserRepository(tx).ResetFailedLogins(ctx, resetToken.UserID); err != nil {
+			return err
+		}
+
+		return s.revokeAllUserSessions(ctx, tx, resetToken.UserID)
+	})
+}
+
+// sendVerificationEmail replaces any outstanding verification token with a new one and emails it to the user
+func (s *AuthService) sendVerificationEmail(ctx context.Context, user *model.User) error {
+	if err := s.verifyTokenRepo.RevokeAllUserTokens(ctx, user.ID); err != nil {
+		return err
+	}
+
+	token, _, err := s.verifyTokenRepo.Create(ctx, user.ID, s.verifyExpiry)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := s.provider.Email.SendEmailVerificationEmail(user.Email, user.Name, token, int(s.verifyExpiry.Minutes())); err != nil {
+			logger.Errorf("Failed to send verification email: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// VerifyEmail consumes a verification token and marks the owner's email as verified
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
+	verificationToken, err := s.verifyTokenRepo.FindByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidVerificationToken
+		}
+		return err
+	}
+
+	if !verificationToken.IsValid() || isDeletedUser(&verificationToken.User) {
+		return ErrInvalidVerificationToken
+	}
+
+	return s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewEmailVerificationTokenRepository(tx).MarkUsed(ctx, verificationToken.ID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidVerificationToken
+			}
+			return err
+		}
+
+		return repository.NewUserRepository(tx).MarkEmailVerified(ctx, verificationToken.UserID)
+	})
+}
+
+// ResendVerificationEmail sends a fresh verification email, throttled per user
+func (s *AuthService) ResendVerificationEmail(ctx context.Context, userID uint) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.IsEmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+
+	latest, err := s.verifyTokenRepo.FindLatestByUser(ctx, user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if latest != nil && timezone.Now().Before(latest.CreatedAt.Add(s.verifyInterval)) {
+		return ErrVerificationThrottled
+	}
+
+	return s.sendVerificationEmail(ctx, user)
 }
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-api/authtoken"
+	"go-api/config"
+	"go-api/domain/auth/entity"
+	"go-api/model"
+	"go-api/repository"
+	"go-api/shared/timezone"
+	"go-api/shared/useragent"
+
+	"gorm.io/gorm"
+)
+
+// maxUserAgentLength matches the size of the access_tokens.user_agent column
+const maxUserAgentLength = 512
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// RecordTokenUsage stores the client IP, user agent and last use time on a database-backed token.
+// Stateless tokens have no row and are skipped. An unchanged session is written back at most
+// once per touch interval, which is also the precision of the idle timeout.
+func (s *AuthService) RecordTokenUsage(ctx context.Context, accessToken *model.AccessToken, ipAddress, userAgent string) error {
+	if accessToken == nil || accessToken.ID == 0 {
+		return nil
+	}
+
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	unchanged := accessToken.IPAddress == ipAddress && accessToken.UserAgent == userAgent
+	if unchanged && accessToken.LastUsedAt != nil && timezone.Now().Before(accessToken.LastUsedAt.Add(config.Get().TokenTouchInterval)) {
+		return nil
+	}
+
+	return repository.NewAccessTokenRepository(s.provider.DB).Touch(ctx, accessToken.ID, ipAddress, userAgent, useragent.Label(userAgent))
+}
+
+// ListSessions returns the active sessions of a user. Database-backed tokens are listed one
+// per access token; stateless JWTs have no row, so each live refresh token family is a session.
+// The session of current, if any, is flagged.
+func (s *AuthService) ListSessions(ctx context.Context, userID uint, current *model.AccessToken) ([]entity.SessionResponse, error) {
+	if config.Get().TokenStrategy == authtoken.StrategyJWT {
+		return s.listRefreshSessions(ctx, userID, current)
+	}
+
+	accessTokens, err := repository.NewAccessTokenRepository(s.provider.DB).FindActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]entity.SessionResponse, 0, len(accessTokens))
+	for _, accessToken := range accessTokens {
+		sessions = append(sessions, entity.SessionResponse{
+			ID:          accessToken.ID,
+			DeviceLabel: accessToken.DeviceLabel,
+			UserAgent:   accessToken.UserAgent,
+			IPAddress:   accessToken.IPAddress,
+			TokenPrefix: accessToken.TokenPrefix,
+			LastUsedAt:  accessToken.LastUsedAt,
+			CreatedAt:   accessToken.CreatedAt,
+			ExpiresAt:   accessToken.ExpiresAt,
+			Current:     current != nil && current.ID != 0 && accessToken.ID == current.ID,
+		})
+	}
+
+	return sessions, nil
+}
+
+// listRefreshSessions builds the sessions from the refresh token families of a user.
+// The session ID is the ID of the family's current refresh token.
+func (s *AuthService) listRefreshSessions(ctx context.Context, userID uint, current *model.AccessToken) ([]entity.SessionResponse, error) {
+	refreshTokens, err := repository.NewRefreshTokenRepository(s.provider.DB).FindActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]entity.SessionResponse, 0, len(refreshTokens))
+	for _, refreshToken := range refreshTokens {
+		sessions = append(sessions, entity.SessionResponse{
+			ID:        refreshToken.ID,
+			CreatedAt: refreshToken.SessionStartedAt,
+			ExpiresAt: refreshToken.ExpiresAt,
+			Current:   current != nil && current.SessionID != "" && refreshToken.FamilyID == current.SessionID,
+		})
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession signs a single device out, including its refresh token family
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID uint) error {
+	if config.Get().TokenStrategy == authtoken.StrategyJWT {
+		refreshToken, err := repository.NewRefreshTokenRepository(s.provider.DB).FindByIDForUser(ctx, sessionID, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSessionNotFound
+			}
+			return err
+		}
+
+		return s.revokeRefreshFamily(ctx, userID, refreshToken.FamilyID)
+	}
+
+	accessTokenRepo := repository.NewAccessTokenRepository(s.provider.DB)
+
+	accessToken, err := accessTokenRepo.FindByIDForUser(ctx, sessionID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+
+	if accessToken.SessionID != "" {
+		return s.revokeRefreshFamily(ctx, userID, accessToken.SessionID)
+	}
+
+	return accessTokenRepo.RevokeByIDs(ctx, []uint{accessToken.ID})
+}
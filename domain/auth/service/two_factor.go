@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-api/config"
+	"go-api/domain/auth/entity"
+	"go-api/model"
+	"go-api/repository"
+	"go-api/shared/logger"
+	"go-api/shared/securetoken"
+	"go-api/shared/timezone"
+	"go-api/shared/totp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// recoveryCodeCount is the number of recovery codes generated per batch
+	recoveryCodeCount = 10
+	// maxChallengeAttempts is the number of wrong codes after which a login challenge is burned
+	maxChallengeAttempts = 5
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotPending     = errors.New("two-factor authentication setup has not been started")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor authentication code")
+	ErrInvalidChallenge        = errors.New("invalid or expired two-factor challenge")
+)
+
+// isTwoFactorEnabled reports whether the user has a confirmed TOTP credential
+func (s *AuthService) isTwoFactorEnabled(ctx context.Context, userID uint) (bool, error) {
+	credential, err := repository.NewTwoFactorRepository(s.provider.DB).FindCredentialByUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return credential.IsEnabled(), nil
+}
+
+// startTwoFactorChallenge issues the short-lived token exchanged for access tokens once the code is provided
+func (s *AuthService) startTwoFactorChallenge(ctx context.Context, userID uint) (*entity.LoginResult, error) {
+	token, challenge, err := repository.NewTwoFactorRepository(s.provider.DB).CreateChallenge(ctx, userID, config.Get().TwoFactorChallengeExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.LoginResult{
+		TwoFactorRequired:  true,
+		ChallengeToken:     token,
+		ChallengeExpiresAt: challenge.ExpiresAt,
+	}, nil
+}
+
+// SetupTwoFactor starts enrollment with a new secret. It is not enforced until confirmed.
+func (s *AuthService) SetupTwoFactor(ctx context.Context, userID uint) (*entity.TwoFactorSetup, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := s.isTwoFactorEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := repository.NewTwoFactorRepository(s.provider.DB).SaveCredential(ctx, userID, secret); err != nil {
+		return nil, err
+	}
+
+	return &entity.TwoFactorSetup{
+		Secret: secret,
+		URI:    totp.URI(secret, config.Get().TwoFactorIssuer, user.Email),
+	}, nil
+}
+
+// ConfirmTwoFactor enables two-factor authentication once the user proves the secret works
+// and returns the initial set of recovery codes
+func (s *AuthService) ConfirmTwoFactor(ctx context.Context, userID uint, code string) ([]string, error) {
+	twoFactorRepo := repository.NewTwoFactorRepository(s.provider.DB)
+
+	credential, err := twoFactorRepo.FindCredentialByUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTwoFactorNotPending
+		}
+		return nil, err
+	}
+	if credential.IsEnabled() {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := totp.Validate(credential.Secret, code, timezone.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txRepo := repository.NewTwoFactorRepository(tx)
+		if err := txRepo.ConfirmCredential(ctx, credential.ID, step); err != nil {
+			return err
+		}
+		return txRepo.ReplaceRecoveryCodes(ctx, userID, codes)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// DisableTwoFactor removes the credential and recovery codes after re-authenticating the user
+func (s *AuthService) DisableTwoFactor(ctx context.Context, userID uint, password, code, recoveryCode string) error {
+	if _, err := s.verifyPassword(ctx, userID, password); err != nil {
+		return err
+	}
+
+	credential, err := s.enabledCredential(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.verifySecondFactor(ctx, tx, credential, code, recoveryCode); err != nil {
+			return err
+		}
+
+		txRepo := repository.NewTwoFactorRepository(tx)
+		if err := txRepo.DeleteRecoveryCodes(ctx, userID); err != nil {
+			return err
+		}
+		return txRepo.DeleteCredential(ctx, userID)
+	})
+}
+
+// RegenerateRecoveryCodes invalidates all recovery codes and returns a new set
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, userID uint, password string) ([]string, error) {
+	if _, err := s.verifyPassword(ctx, userID, password); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.enabledCredential(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := repository.NewTwoFactorRepository(s.provider.DB).ReplaceRecoveryCodes(ctx, userID, codes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// CompleteTwoFactorChallenge finishes a two-step login with a TOTP or recovery code
+func (s *AuthService) CompleteTwoFactorChallenge(ctx context.Context, challengeToken, code, recoveryCode string) (*entity.TokenPair, error) {
+	twoFactorRepo := repository.NewTwoFactorRepository(s.provider.DB)
+
+	challenge, err := twoFactorRepo.FindChallengeByToken(ctx, challengeToken)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidChallenge
+		}
+		return nil, err
+	}
+	if !challenge.IsValid(maxChallengeAttempts) {
+		return nil, ErrInvalidChallenge
+	}
+	if err := loginableUser(&challenge.User, ErrInvalidChallenge); err != nil {
+		return nil, err
+	}
+
+	credential, err := s.enabledCredential(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.verifySecondFactor(ctx, tx, credential, code, recoveryCode); err != nil {
+			return err
+		}
+
+		if err := repository.NewTwoFactorRepository(tx).MarkChallengeUsed(ctx, challenge.ID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidChallenge
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			if incErr := twoFactorRepo.IncrementChallengeAttempts(ctx, challenge.ID); incErr != nil {
+				logger.Errorf("Failed to record two-factor attempt for challenge %d: %v", challenge.ID, incErr)
+			}
+			// Wrong codes also count toward the account lockout, a new challenge does not reset them
+			if lockErr := s.recordFailedLogin(ctx, &challenge.User); lockErr != nil {
+				if errors.Is(lockErr, ErrAccountLocked) {
+					return nil, lockErr
+				}
+				logger.Errorf("Failed to record failed two-factor attempt for user %d: %v", challenge.UserID, lockErr)
+			}
+		}
+		return nil, err
+	}
+
+	if err := s.clearFailedLogins(ctx, &challenge.User); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(ctx, &challenge.User)
+}
+
+// enabledCredential returns the confirmed TOTP credential of a user
+func (s *AuthService) enabledCredential(ctx context.Context, userID uint) (*model.TwoFactorCredential, error) {
+	credential, err := repository.NewTwoFactorRepository(s.provider.DB).FindCredentialByUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTwoFactorNotEnabled
+		}
+		return nil, err
+	}
+	if !credential.IsEnabled() {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	return credential, nil
+}
+
+// verifySecondFactor accepts either a fresh TOTP code or an unused recovery code
+func (s *AuthService) verifySecondFactor(ctx context.Context, db *gorm.DB, credential *model.TwoFactorCredential, code, recoveryCode string) error {
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+
+	if code != "" {
+		step, ok := totp.Validate(credential.Secret, code, timezone.Now())
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+		// A code can only be used once within its validity window
+		if err := twoFactorRepo.UseStep(ctx, credential.ID, step); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidTwoFactorCode
+			}
+			return err
+		}
+		return nil
+	}
+
+	if recoveryCode != "" {
+		if err := twoFactorRepo.UseRecoveryCode(ctx, credential.UserID, normalizeRecoveryCode(recoveryCode)); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidTwoFactorCode
+			}
+			return err
+		}
+		return nil
+	}
+
+	return ErrInvalidTwoFactorCode
+}
+
+// generateRecoveryCodes returns a batch of random codes formatted as xxxx-xxxx-xxxx-xxxx
+func generateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := securetoken.Generate(8)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, raw[0:4]+"-"+raw[4:8]+"-"+raw[8:12]+"-"+raw[12:16])
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode accepts codes typed with different casing or surrounding spaces
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if len(code) == 16 && !strings.Contains(code, "-") {
+		code = code[0:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:16]
+	}
+	return code
+}
@@ -0,0 +1,125 @@
+package entity
+
+import (
+	"go-api/model"
+	"time"
+)
+
+// CreateClientRequest represents the request to register an OAuth client
+type CreateClientRequest struct {
+	Name         string   `json:"name" validate:"required,min=2,max=100"`
+	RedirectURIs []string `json:"redirect_uris" validate:"omitempty,dive,url"`
+	GrantTypes   []string `json:"grant_types" validate:"required,min=1,dive,oneof=authorization_code refresh_token client_credentials"`
+	Scopes       []string `json:"scopes" validate:"omitempty,dive,oneof=profile email read write"`
+	Public       bool     `json:"public"`
+}
+
+// ClientResponse represents a registered OAuth client without its secret
+type ClientResponse struct {
+	ID           uint      `json:"id"`
+	ClientID     string    `json:"client_id"`
+	Name         string    `json:"name"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	GrantTypes   []string  `json:"grant_types"`
+	Scopes       []string  `json:"scopes"`
+	Public       bool      `json:"public"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// NewClientResponse builds the response payload of a client
+func NewClientResponse(client *model.OAuthClient) ClientResponse {
+	return ClientResponse{
+		ID:           client.ID,
+		ClientID:     client.ClientID,
+		Name:         client.Name,
+		RedirectURIs: client.RedirectURIList(),
+		GrantTypes:   client.GrantTypeList(),
+		Scopes:       client.ScopeList(),
+		Public:       client.Public,
+		CreatedAt:    client.CreatedAt,
+	}
+}
+
+// CurrentClientResponse describes the client an access token was issued to
+type CurrentClientResponse struct {
+	Client    ClientResponse `json:"client"`
+	Scopes    []string       `json:"scopes"`
+	ExpiresAt time.Time      `json:"expires_at"`
+}
+
+// AuthorizeRequest represents an authorization request of a client (RFC 6749 section 4.1.1, RFC 7636)
+type AuthorizeRequest struct {
+	ResponseType        string `json:"response_type" query:"response_type" validate:"required"`
+	ClientID            string `json:"client_id" query:"client_id" validate:"required"`
+	RedirectURI         string `json:"redirect_uri" query:"redirect_uri" validate:"required"`
+	Scope               string `json:"scope" query:"scope"`
+	State               string `json:"state" query:"state"`
+	CodeChallenge       string `json:"code_challenge" query:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method" query:"code_challenge_method"`
+}
+
+// ConsentRequest represents the user's answer to an authorization request
+type ConsentRequest struct {
+	AuthorizeRequest
+	Approve bool `json:"approve"`
+}
+
+// AuthorizeResult represents the outcome of an authorization request. Either the user
+// must be asked for consent, or the client is redirected with a code or an error.
+type AuthorizeResult struct {
+	ConsentRequired bool     `json:"consent_required"`
+	ClientName      string   `json:"client_name,omitempty"`
+	Scopes          []string `json:"scopes,omitempty"`
+	RedirectTo      string   `json:"redirect_to,omitempty"`
+}
+
+// TokenRequest represents a token endpoint request (RFC 6749 sections 4.1.3, 4.4.2 and 6)
+type TokenRequest struct {
+	GrantType    string `form:"grant_type"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	RefreshToken string `form:"refresh_token"`
+	Scope        string `form:"scope"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+// TokenResponse represents a successful token response (RFC 6749 section 5.1)
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// TokenActionRequest represents an introspection (RFC 7662) or revocation (RFC 7009) request
+type TokenActionRequest struct {
+	Token         string `form:"token"`
+	TokenTypeHint string `form:"token_type_hint"`
+	ClientID      string `form:"client_id"`
+	ClientSecret  string `form:"client_secret"`
+}
+
+// IntrospectionResponse represents the state of a token (RFC 7662 section 2.2)
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+}
+
+// ConsentResponse represents an application the user granted access to
+type ConsentResponse struct {
+	ID         uint      `json:"id"`
+	ClientID   string    `json:"client_id"`
+	ClientName string    `json:"client_name"`
+	Scopes     []string  `json:"scopes"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
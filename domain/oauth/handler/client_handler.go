@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"errors"
+	"go-api/domain/oauth/entity"
+	"go-api/domain/oauth/service"
+	"go-api/model"
+	"go-api/shared/logger"
+	"go-api/shared/response"
+	"go-api/shared/validator"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (h *OAuthHandler) CreateClient(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	var req entity.CreateClientRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	secret, client, err := h.OAuthService.CreateClient(ctx, userID.(uint), req)
+	if err != nil {
+		if errors.Is(err, service.ErrPublicClientGrant) ||
+			errors.Is(err, service.ErrRedirectURIRequired) ||
+			errors.Is(err, service.ErrInvalidRedirectURI) {
+			return response.BadRequest(c, err, "Invalid client configuration")
+		}
+		return response.InternalServerError(c, err, "Failed to create OAuth client")
+	}
+
+	logger.Infof("OAuth client %s created by admin %v", client.ClientID, userID)
+
+	// The secret is only shown once
+	data := fiber.Map{"client": entity.NewClientResponse(client)}
+	if secret != "" {
+		data["client_secret"] = secret
+	}
+
+	return response.Created(c, data, "OAuth client created successfully")
+}
+
+func (h *OAuthHandler) ListClients(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	clients, err := h.OAuthService.ListClients(ctx)
+	if err != nil {
+		return response.InternalServerError(c, err, "Failed to retrieve OAuth clients")
+	}
+
+	result := make([]entity.ClientResponse, 0, len(clients))
+	for i := range clients {
+		result = append(result, entity.NewClientResponse(&clients[i]))
+	}
+
+	return response.Success(c, result)
+}
+
+func (h *OAuthHandler) GetClient(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid client ID")
+	}
+
+	ctx := c.UserContext()
+
+	client, err := h.OAuthService.GetClient(ctx, uint(id))
+	if err != nil {
+		if errors.Is(err, service.ErrClientNotFound) {
+			return response.NotFound(c, "OAuth client not found")
+		}
+		return response.InternalServerError(c, err, "Failed to retrieve OAuth client")
+	}
+
+	return response.Success(c, entity.NewClientResponse(client))
+}
+
+func (h *OAuthHandler) DeleteClient(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid client ID")
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.OAuthService.DeleteClient(ctx, uint(id)); err != nil {
+		if errors.Is(err, service.ErrClientNotFound) {
+			return response.NotFound(c, "OAuth client not found")
+		}
+		return response.InternalServerError(c, err, "Failed to delete OAuth client")
+	}
+
+	logger.Infof("OAuth client %d deleted by admin %v", id, c.Locals("user_id"))
+
+	return response.Success(c, nil, "OAuth client deleted successfully")
+}
+
+// CurrentClient reports the client and scopes of the calling access token, so machine
+// clients using the client credentials grant can check what their token allows
+func (h *OAuthHandler) CurrentClient(c *fiber.Ctx) error {
+	oauthToken, ok := c.Locals("oauth_token").(*model.OAuthToken)
+	if !ok {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	return response.Success(c, entity.CurrentClientResponse{
+		Client:    entity.NewClientResponse(&oauthToken.Client),
+		Scopes:    oauthToken.ScopeList(),
+		ExpiresAt: oauthToken.ExpiresAt,
+	}, "OAuth client retrieved successfully")
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"go-api/domain/oauth/entity"
+	"go-api/domain/oauth/service"
+	"go-api/shared/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListConsents returns the applications the user has granted access to
+func (h *OAuthHandler) ListConsents(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	ctx := c.UserContext()
+
+	consents, err := h.OAuthService.ListConsents(ctx, userID.(uint))
+	if err != nil {
+		return response.InternalServerError(c, err, "Failed to retrieve authorized applications")
+	}
+
+	result := make([]entity.ConsentResponse, 0, len(consents))
+	for _, consent := range consents {
+		// Consents of deleted clients are meaningless, their tokens no longer validate
+		if consent.Client.ID == 0 {
+			continue
+		}
+		result = append(result, entity.ConsentResponse{
+			ID:         consent.ID,
+			ClientID:   consent.Client.ClientID,
+			ClientName: consent.Client.Name,
+			Scopes:     consent.ScopeList(),
+			CreatedAt:  consent.CreatedAt,
+			UpdatedAt:  consent.UpdatedAt,
+		})
+	}
+
+	return response.Success(c, result)
+}
+
+// RevokeConsent withdraws an application's access and revokes its tokens
+func (h *OAuthHandler) RevokeConsent(c *fiber.Ctx) error {
+	// Get user from context (set by auth middleware)
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid consent ID")
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.OAuthService.RevokeConsent(ctx, userID.(uint), uint(id)); err != nil {
+		if errors.Is(err, service.ErrConsentNotFound) {
+			return response.NotFound(c, "Authorized application not found")
+		}
+		return response.InternalServerError(c, err, "Failed to revoke access")
+	}
+
+	return response.Success(c, nil, "Access revoked successfully")
+}
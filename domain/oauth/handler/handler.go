@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"go-api/app"
+	"go-api/domain/oauth/entity"
+	"go-api/domain/oauth/service"
+	"go-api/shared/logger"
+	"go-api/shared/response"
+	"go-api/shared/validator"
+	"net/url"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OAuthHandler struct {
+	OAuthService *service.OAuthService
+}
+
+func NewOAuthHandler(p *app.Provider) *OAuthHandler {
+	return &OAuthHandler{
+		OAuthService: service.NewOAuthService(p),
+	}
+}
+
+// Authorize answers an authorization request of the signed in user. The consent screen
+// either shows the requested scopes or sends the browser to redirect_to.
+func (h *OAuthHandler) Authorize(c *fiber.Ctx) error {
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	var req entity.AuthorizeRequest
+	if err := c.QueryParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid query parameters")
+	}
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	result, err := h.OAuthService.Authorize(ctx, userID.(uint), req)
+	if err != nil {
+		return authorizeErrorResponse(c, err)
+	}
+
+	return response.Success(c, result)
+}
+
+// Consent records the user's decision on an authorization request
+func (h *OAuthHandler) Consent(c *fiber.Ctx) error {
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	var req entity.ConsentRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req.AuthorizeRequest); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	result, err := h.OAuthService.Consent(ctx, userID.(uint), req)
+	if err != nil {
+		return authorizeErrorResponse(c, err)
+	}
+
+	return response.Success(c, result)
+}
+
+// authorizeErrorResponse reports errors for which the client cannot be redirected
+func authorizeErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrClientNotFound):
+		return response.BadRequest(c, err, "Unknown client")
+	case errors.Is(err, service.ErrInvalidRedirectURI):
+		return response.BadRequest(c, err, "Invalid redirect URI")
+	default:
+		return response.InternalServerError(c, err, "Failed to process authorization request")
+	}
+}
+
+// Token is the token endpoint. Unlike the rest of the API it answers in the format of
+// RFC 6749 section 5 so standard OAuth client libraries can use it.
+func (h *OAuthHandler) Token(c *fiber.Ctx) error {
+	var req entity.TokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return oauthErrorResponse(c, service.ErrInvalidRequest)
+	}
+
+	clientID, clientSecret, err := clientCredentials(c, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	ctx := c.UserContext()
+
+	result, err := h.OAuthService.Token(ctx, clientID, clientSecret, req)
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	noStore(c)
+	return c.JSON(result)
+}
+
+// Introspect is the token introspection endpoint (RFC 7662)
+func (h *OAuthHandler) Introspect(c *fiber.Ctx) error {
+	var req entity.TokenActionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return oauthErrorResponse(c, service.ErrInvalidRequest)
+	}
+
+	clientID, clientSecret, err := clientCredentials(c, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	ctx := c.UserContext()
+
+	result, err := h.OAuthService.Introspect(ctx, clientID, clientSecret, req.Token)
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	noStore(c)
+	return c.JSON(result)
+}
+
+// Revoke is the token revocation endpoint (RFC 7009)
+func (h *OAuthHandler) Revoke(c *fiber.Ctx) error {
+	var req entity.TokenActionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return oauthErrorResponse(c, service.ErrInvalidRequest)
+	}
+
+	clientID, clientSecret, err := clientCredentials(c, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.OAuthService.Revoke(ctx, clientID, clientSecret, req.Token); err != nil {
+		return oauthErrorResponse(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// clientCredentials reads the client credentials from the HTTP Basic authorization header,
+// falling back to the form parameters (RFC 6749 section 2.3.1). Using both is an error.
+func clientCredentials(c *fiber.Ctx, formID, formSecret string) (string, string, error) {
+	authHeader := c.Get("Authorization")
+	if authHeader == "" {
+		return formID, formSecret, nil
+	}
+
+	if !strings.HasPrefix(authHeader, "Basic ") || formSecret != "" {
+		return "", "", service.ErrInvalidClient
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(authHeader[6:])
+	if err != nil {
+		return "", "", service.ErrInvalidClient
+	}
+
+	rawID, rawSecret, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return "", "", service.ErrInvalidClient
+	}
+
+	// Credentials are form-encoded before being put in the header
+	clientID, err := url.QueryUnescape(rawID)
+	if err != nil {
+		return "", "", service.ErrInvalidClient
+	}
+	clientSecret, err := url.QueryUnescape(rawSecret)
+	if err != nil {
+		return "", "", service.ErrInvalidClient
+	}
+
+	return clientID, clientSecret, nil
+}
+
+// oauthErrorResponse sends an error in the format of RFC 6749 section 5.2
+func oauthErrorResponse(c *fiber.Ctx, err error) error {
+	status := fiber.StatusBadRequest
+	var code string
+
+	switch {
+	case errors.Is(err, service.ErrInvalidRequest):
+		code = "invalid_request"
+	case errors.Is(err, service.ErrInvalidClient):
+		status = fiber.StatusUnauthorized
+		code = "invalid_client"
+		if strings.HasPrefix(c.Get("Authorization"), "Basic ") {
+			c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+		}
+	case errors.Is(err, service.ErrInvalidGrant):
+		code = "invalid_grant"
+	case errors.Is(err, service.ErrUnauthorizedClient):
+		code = "unauthorized_client"
+	case errors.Is(err, service.ErrUnsupportedGrantType):
+		code = "unsupported_grant_type"
+	case errors.Is(err, service.ErrInvalidScope):
+		code = "invalid_scope"
+	default:
+		logger.Errorf("OAuth request to %s failed: %v", c.Path(), err)
+		status = fiber.StatusInternalServerError
+		code = "server_error"
+		err = errors.New("the server encountered an unexpected error")
+	}
+
+	noStore(c)
+	return c.Status(status).JSON(fiber.Map{
+		"error":             code,
+		"error_description": err.Error(),
+	})
+}
+
+// noStore prevents caching of responses containing tokens (RFC 6749 section 5.1)
+func noStore(c *fiber.Ctx) {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set(fiber.HeaderPragma, "no-cache")
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-api/domain/oauth/entity"
+	"go-api/model"
+	"net/url"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// PKCECodeChallengeMethod is the only supported PKCE method, "plain" is rejected
+const PKCECodeChallengeMethod = "S256"
+
+// Authorize handles an authorization request of a signed in user (RFC 6749 section 4.1.1).
+// The client is redirected with a code right away when the user already consented to the
+// requested scopes, otherwise the result asks for the user's consent.
+//
+// An error is only returned when the client or redirect URI cannot be trusted. Other
+// problems are reported to the client through the redirect URI.
+func (s *OAuthService) Authorize(ctx context.Context, userID uint, req entity.AuthorizeRequest) (*entity.AuthorizeResult, error) {
+	client, err := s.authorizeClient(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes, errCode := validateAuthorizeRequest(client, req)
+	if errCode != "" {
+		return redirectResult(req.RedirectURI, url.Values{"error": {errCode}}, req.State), nil
+	}
+
+	consent, err := s.consentRepo.FindByUserAndClient(ctx, userID, client.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if consent == nil || !consent.Covers(scopes) {
+		return &entity.AuthorizeResult{
+			ConsentRequired: true,
+			ClientName:      client.Name,
+			Scopes:          scopes,
+		}, nil
+	}
+
+	return s.issueCode(ctx, userID, client, scopes, req)
+}
+
+// Consent records the user's decision on an authorization request and redirects the client
+// with either a code or an access_denied error.
+func (s *OAuthService) Consent(ctx context.Context, userID uint, req entity.ConsentRequest) (*entity.AuthorizeResult, error) {
+	client, err := s.authorizeClient(ctx, req.AuthorizeRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes, errCode := validateAuthorizeRequest(client, req.AuthorizeRequest)
+	if errCode != "" {
+		return redirectResult(req.RedirectURI, url.Values{"error": {errCode}}, req.State), nil
+	}
+
+	if !req.Approve {
+		return redirectResult(req.RedirectURI, url.Values{"error": {"access_denied"}}, req.State), nil
+	}
+
+	// Keep scopes granted earlier so approving a narrower request does not revoke them
+	granted := scopes
+	consent, err := s.consentRepo.FindByUserAndClient(ctx, userID, client.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if consent != nil {
+		granted = uniqueFields(append(consent.ScopeList(), scopes...))
+	}
+
+	if err := s.consentRepo.Save(ctx, userID, client.ID, strings.Join(granted, " ")); err != nil {
+		return nil, err
+	}
+
+	return s.issueCode(ctx, userID, client, scopes, req.AuthorizeRequest)
+}
+
+// authorizeClient finds the client of an authorization request and checks the redirect URI
+func (s *OAuthService) authorizeClient(ctx context.Context, req entity.AuthorizeRequest) (*model.OAuthClient, error) {
+	client, err := s.clientRepo.FindByClientID(ctx, req.ClientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrClientNotFound
+		}
+		return nil, err
+	}
+
+	if !client.AllowsRedirectURI(req.RedirectURI) {
+		return nil, ErrInvalidRedirectURI
+	}
+
+	return client, nil
+}
+
+// validateAuthorizeRequest returns the granted scopes, or the RFC 6749 error code to send
+// back to the client. PKCE is required for every client.
+func validateAuthorizeRequest(client *model.OAuthClient, req entity.AuthorizeRequest) ([]string, string) {
+	if req.ResponseType != "code" {
+		return nil, "unsupported_response_type"
+	}
+	if !client.AllowsGrant(model.OAuthGrantAuthorizationCode) {
+		return nil, "unauthorized_client"
+	}
+	if req.CodeChallenge == "" || req.CodeChallengeMethod != PKCECodeChallengeMethod {
+		return nil, "invalid_request"
+	}
+
+	scopes, err := resolveScopes(client, req.Scope)
+	if err != nil {
+		return nil, "invalid_scope"
+	}
+
+	return scopes, ""
+}
+
+func (s *OAuthService) issueCode(ctx context.Context, userID uint, client *model.OAuthClient, scopes []string, req entity.AuthorizeRequest) (*entity.AuthorizeResult, error) {
+	code, err := s.codeRepo.Create(ctx, &model.OAuthAuthorizationCode{
+		ClientID:            client.ID,
+		UserID:              userID,
+		RedirectURI:         req.RedirectURI,
+		Scopes:              strings.Join(scopes, " "),
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+	}, s.codeExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	return redirectResult(req.RedirectURI, url.Values{"code": {code}}, req.State), nil
+}
+
+// redirectResult appends the parameters and state to the redirect URI of the client
+func redirectResult(redirectURI string, params url.Values, state string) *entity.AuthorizeResult {
+	if state != "" {
+		params.Set("state", state)
+	}
+
+	u, _ := url.Parse(redirectURI) // Registered redirect URIs are validated on creation
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+
+	return &entity.AuthorizeResult{RedirectTo: u.String()}
+}
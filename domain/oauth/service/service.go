@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api/app"
+	"go-api/config"
+	"go-api/domain/oauth/entity"
+	"go-api/model"
+	"go-api/repository"
+	"go-api/shared/constant"
+	"go-api/shared/securetoken"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Errors of the token, introspection and revocation endpoints map to the error codes
+// of RFC 6749 section 5.2 in the handler.
+var (
+	ErrInvalidRequest       = errors.New("the request is missing a parameter or is otherwise malformed")
+	ErrInvalidClient        = errors.New("client authentication failed")
+	ErrInvalidGrant         = errors.New("the grant is invalid, expired, revoked or was issued to another client")
+	ErrUnauthorizedClient   = errors.New("the client is not allowed to use this grant type")
+	ErrUnsupportedGrantType = errors.New("the grant type is not supported")
+	ErrInvalidScope         = errors.New("the requested scope is invalid or exceeds the granted scope")
+	ErrInvalidToken         = errors.New("invalid or expired OAuth token")
+	ErrClientNotFound       = errors.New("OAuth client not found")
+	ErrInvalidRedirectURI   = errors.New("redirect URI is not valid for this client")
+	ErrRedirectURIRequired  = errors.New("at least one redirect URI is required for the authorization_code grant")
+	ErrPublicClientGrant    = errors.New("public clients cannot use the client_credentials grant")
+	ErrConsentNotFound      = errors.New("consent not found")
+)
+
+type OAuthService struct {
+	provider      *app.Provider
+	clientRepo    *repository.OAuthClientRepository
+	codeRepo      *repository.OAuthAuthorizationCodeRepository
+	consentRepo   *repository.OAuthConsentRepository
+	tokenRepo     *repository.OAuthTokenRepository
+	codeExpiry    time.Duration
+	accessExpiry  time.Duration
+	refreshExpiry time.Duration
+}
+
+func NewOAuthService(p *app.Provider) *OAuthService {
+	return &OAuthService{
+		provider:      p,
+		clientRepo:    repository.NewOAuthClientRepository(p.DB),
+		codeRepo:      repository.NewOAuthAuthorizationCodeRepository(p.DB),
+		consentRepo:   repository.NewOAuthConsentRepository(p.DB),
+		tokenRepo:     repository.NewOAuthTokenRepository(p.DB),
+		codeExpiry:    config.Get().OAuthCodeExpiry,
+		accessExpiry:  config.Get().OAuthAccessTokenExpiry,
+		refreshExpiry: config.Get().OAuthRefreshTokenExpiry,
+	}
+}
+
+// CreateClient registers a client and returns its secret, which is empty for public clients
+func (s *OAuthService) CreateClient(ctx context.Context, createdByID uint, req entity.CreateClientRequest) (string, *model.OAuthClient, error) {
+	grantTypes := uniqueFields(req.GrantTypes)
+	if req.Public && slices.Contains(grantTypes, model.OAuthGrantClientCredentials) {
+		return "", nil, ErrPublicClientGrant
+	}
+
+	redirectURIs := uniqueFields(req.RedirectURIs)
+	if slices.Contains(grantTypes, model.OAuthGrantAuthorizationCode) && len(redirectURIs) == 0 {
+		return "", nil, ErrRedirectURIRequired
+	}
+	for _, redirectURI := range redirectURIs {
+		if !isValidRedirectURI(redirectURI) {
+			return "", nil, fmt.Errorf("%w: %s", ErrInvalidRedirectURI, redirectURI)
+		}
+	}
+
+	client := &model.OAuthClient{
+		Name:         req.Name,
+		RedirectURIs: strings.Join(redirectURIs, " "),
+		GrantTypes:   strings.Join(grantTypes, " "),
+		Scopes:       strings.Join(uniqueFields(req.Scopes), " "),
+		Public:       req.Public,
+		CreatedByID:  &createdByID,
+	}
+
+	secret, err := s.clientRepo.Create(ctx, client)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return secret, client, nil
+}
+
+func (s *OAuthService) ListClients(ctx context.Context) ([]model.OAuthClient, error) {
+	return s.clientRepo.List(ctx)
+}
+
+func (s *OAuthService) GetClient(ctx context.Context, id uint) (*model.OAuthClient, error) {
+	client, err := s.clientRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrClientNotFound
+		}
+		return nil, err
+	}
+	return client, nil
+}
+
+// DeleteClient removes a client. Its tokens stop validating because they are only
+// accepted while the client exists.
+func (s *OAuthService) DeleteClient(ctx context.Context, id uint) error {
+	if err := s.clientRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrClientNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ListConsents returns the applications a user has granted access to
+func (s *OAuthService) ListConsents(ctx context.Context, userID uint) ([]model.OAuthConsent, error) {
+	return s.consentRepo.FindByUser(ctx, userID)
+}
+
+// RevokeConsent withdraws the access granted to an application, including all its tokens
+func (s *OAuthService) RevokeConsent(ctx context.Context, userID, consentID uint) error {
+	consent, err := s.consentRepo.FindByIDForUser(ctx, consentID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrConsentNotFound
+		}
+		return err
+	}
+
+	return s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewOAuthConsentRepository(tx).Delete(ctx, consent.ID); err != nil {
+			return err
+		}
+		return repository.NewOAuthTokenRepository(tx).RevokeByUserAndClient(ctx, userID, consent.ClientID)
+	})
+}
+
+// authenticateClient checks the credentials of a client (RFC 6749 section 2.3).
+// Public clients only identify themselves, confidential clients must present their secret.
+func (s *OAuthService) authenticateClient(ctx context.Context, clientID, clientSecret string) (*model.OAuthClient, error) {
+	if clientID == "" {
+		return nil, ErrInvalidClient
+	}
+
+	client, err := s.clientRepo.FindByClientID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidClient
+		}
+		return nil, err
+	}
+
+	if client.Public {
+		if clientSecret != "" {
+			return nil, ErrInvalidClient
+		}
+		return client, nil
+	}
+
+	if clientSecret == "" || !securetoken.Equal(client.ClientSecretHash, securetoken.Hash(clientSecret)) {
+		return nil, ErrInvalidClient
+	}
+
+	return client, nil
+}
+
+// resolveScopes returns the requested scopes, or all scopes of the client when none
+// were requested. Every scope must be known and allowed for the client.
+func resolveScopes(client *model.OAuthClient, scope string) ([]string, error) {
+	requested := uniqueFields(strings.Fields(scope))
+	if len(requested) == 0 {
+		return client.ScopeList(), nil
+	}
+
+	for _, s := range requested {
+		if !slices.Contains(constant.OAuthScopes, s) || !client.AllowsScope(s) {
+			return nil, ErrInvalidScope
+		}
+	}
+	return requested, nil
+}
+
+// isValidRedirectURI accepts absolute URIs without a fragment (RFC 6749 section 3.1.2)
+func isValidRedirectURI(redirectURI string) bool {
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return false
+	}
+	return u.Scheme != "" && u.Host != "" && u.Fragment == "" && !strings.ContainsAny(redirectURI, " \t\n")
+}
+
+// uniqueFields trims values and removes empty and duplicate entries, keeping their order
+func uniqueFields(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value != "" && !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"go-api/domain/oauth/entity"
+	"go-api/model"
+	"go-api/repository"
+	"go-api/shared/securetoken"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Token handles a token endpoint request (RFC 6749 sections 4.1.3, 4.4 and 6).
+// The client credentials come from the form or the HTTP Basic authorization header.
+func (s *OAuthService) Token(ctx context.Context, clientID, clientSecret string, req entity.TokenRequest) (*entity.TokenResponse, error) {
+	client, err := s.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GrantType {
+	case model.OAuthGrantAuthorizationCode:
+		return s.exchangeAuthorizationCode(ctx, client, req)
+	case model.OAuthGrantRefreshToken:
+		return s.refreshToken(ctx, client, req)
+	case model.OAuthGrantClientCredentials:
+		return s.clientCredentials(ctx, client, req)
+	case "":
+		return nil, ErrInvalidRequest
+	default:
+		return nil, ErrUnsupportedGrantType
+	}
+}
+
+func (s *OAuthService) exchangeAuthorizationCode(ctx context.Context, client *model.OAuthClient, req entity.TokenRequest) (*entity.TokenResponse, error) {
+	if !client.AllowsGrant(model.OAuthGrantAuthorizationCode) {
+		return nil, ErrUnauthorizedClient
+	}
+	if req.Code == "" || req.RedirectURI == "" || req.CodeVerifier == "" {
+		return nil, ErrInvalidRequest
+	}
+
+	authCode, err := s.codeRepo.FindByCode(ctx, req.Code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidGrant
+		}
+		return nil, err
+	}
+
+	if authCode.ClientID != client.ID {
+		return nil, ErrInvalidGrant
+	}
+
+	// A replayed code means it leaked, so the tokens issued for it are revoked (RFC 6749 section 4.1.2)
+	if authCode.UsedAt != nil {
+		if err := s.tokenRepo.RevokeGrant(ctx, authCode.GrantID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidGrant
+	}
+
+	if authCode.IsExpired() || authCode.User.ID == 0 || authCode.RedirectURI != req.RedirectURI {
+		return nil, ErrInvalidGrant
+	}
+
+	if !verifyCodeChallenge(authCode.CodeChallenge, req.CodeVerifier) {
+		return nil, ErrInvalidGrant
+	}
+
+	var result *entity.TokenResponse
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewOAuthAuthorizationCodeRepository(tx).MarkUsed(ctx, authCode.ID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidGrant
+			}
+			return err
+		}
+
+		var err error
+		withRefresh := client.AllowsGrant(model.OAuthGrantRefreshToken)
+		result, err = s.issueTokens(ctx, repository.NewOAuthTokenRepository(tx), client, &authCode.UserID, authCode.Scopes, authCode.GrantID, withRefresh)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// refreshToken rotates a refresh token. Presenting a rotated token again revokes the
+// whole grant because either the client or an attacker holds a stolen copy.
+func (s *OAuthService) refreshToken(ctx context.Context, client *model.OAuthClient, req entity.TokenRequest) (*entity.TokenResponse, error) {
+	if !client.AllowsGrant(model.OAuthGrantRefreshToken) {
+		return nil, ErrUnauthorizedClient
+	}
+	if req.RefreshToken == "" {
+		return nil, ErrInvalidRequest
+	}
+
+	refreshToken, err := s.tokenRepo.FindByToken(ctx, req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidGrant
+		}
+		return nil, err
+	}
+
+	if refreshToken.TokenType != model.OAuthTokenTypeRefresh || refreshToken.ClientID != client.ID {
+		return nil, ErrInvalidGrant
+	}
+
+	if refreshToken.UsedAt != nil {
+		if err := s.tokenRepo.RevokeGrant(ctx, refreshToken.GrantID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidGrant
+	}
+
+	if !refreshToken.IsActive() || refreshToken.User == nil {
+		return nil, ErrInvalidGrant
+	}
+
+	// The client may ask for a narrower scope, never a wider one (RFC 6749 section 6)
+	scopes := refreshToken.ScopeList()
+	if requested := uniqueFields(strings.Fields(req.Scope)); len(requested) > 0 {
+		for _, scope := range requested {
+			if !refreshToken.HasScope(scope) {
+				return nil, ErrInvalidScope
+			}
+		}
+		scopes = requested
+	}
+
+	var result *entity.TokenResponse
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tokenRepo := repository.NewOAuthTokenRepository(tx)
+		if err := tokenRepo.MarkUsed(ctx, refreshToken.ID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidGrant
+			}
+			return err
+		}
+
+		var err error
+		result, err = s.issueTokens(ctx, tokenRepo, client, refreshToken.UserID, strings.Join(scopes, " "), refreshToken.GrantID, true)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// clientCredentials issues a token acting on behalf of the client itself, without a user
+func (s *OAuthService) clientCredentials(ctx context.Context, client *model.OAuthClient, req entity.TokenRequest) (*entity.TokenResponse, error) {
+	if client.Public || !client.AllowsGrant(model.OAuthGrantClientCredentials) {
+		return nil, ErrUnauthorizedClient
+	}
+
+	scopes, err := resolveScopes(client, req.Scope)
+	if err != nil {
+		return nil, err
+	}
+
+	grantID, err := s.tokenRepo.NewGrantID()
+	if err != nil {
+		return nil, err
+	}
+
+	// No refresh token, the client can simply request a new access token (RFC 6749 section 4.4.3)
+	return s.issueTokens(ctx, s.tokenRepo, client, nil, strings.Join(scopes, " "), grantID, false)
+}
+
+func (s *OAuthService) issueTokens(ctx context.Context, tokenRepo *repository.OAuthTokenRepository, client *model.OAuthClient, userID *uint, scopes, grantID string, withRefresh bool) (*entity.TokenResponse, error) {
+	accessToken, _, err := tokenRepo.Create(ctx, model.OAuthTokenTypeAccess, client.ID, userID, scopes, grantID, s.accessExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.accessExpiry.Seconds()),
+		Scope:       scopes,
+	}
+
+	if withRefresh {
+		result.RefreshToken, _, err = tokenRepo.Create(ctx, model.OAuthTokenTypeRefresh, client.ID, userID, scopes, grantID, s.refreshExpiry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Introspect reports the state of a token to an authenticated confidential client (RFC 7662).
+// Unknown, expired and revoked tokens are all reported as inactive.
+func (s *OAuthService) Introspect(ctx context.Context, clientID, clientSecret, token string) (*entity.IntrospectionResponse, error) {
+	client, err := s.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if client.Public {
+		return nil, ErrInvalidClient
+	}
+	if token == "" {
+		return nil, ErrInvalidRequest
+	}
+
+	oauthToken, err := s.tokenRepo.FindByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &entity.IntrospectionResponse{Active: false}, nil
+		}
+		return nil, err
+	}
+
+	if !oauthToken.IsActive() || oauthToken.Client.ID == 0 {
+		return &entity.IntrospectionResponse{Active: false}, nil
+	}
+
+	result := &entity.IntrospectionResponse{
+		Active:    true,
+		Scope:     oauthToken.Scopes,
+		ClientID:  oauthToken.Client.ClientID,
+		TokenType: oauthToken.TokenType,
+		Exp:       oauthToken.ExpiresAt.Unix(),
+		Iat:       oauthToken.CreatedAt.Unix(),
+	}
+	if oauthToken.User != nil {
+		result.Username = oauthToken.User.Email
+		result.Sub = strconv.FormatUint(uint64(oauthToken.User.ID), 10)
+	}
+
+	return result, nil
+}
+
+// Revoke invalidates a token of the calling client (RFC 7009). Revoking a refresh token also
+// revokes the access tokens of its grant. Unknown tokens and tokens of other clients are
+// ignored so the response does not reveal whether a token exists.
+func (s *OAuthService) Revoke(ctx context.Context, clientID, clientSecret, token string) error {
+	client, err := s.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return ErrInvalidRequest
+	}
+
+	oauthToken, err := s.tokenRepo.FindByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if oauthToken.ClientID != client.ID {
+		return nil
+	}
+
+	if oauthToken.TokenType == model.OAuthTokenTypeRefresh {
+		return s.tokenRepo.RevokeGrant(ctx, oauthToken.GrantID)
+	}
+	return s.tokenRepo.Revoke(ctx, oauthToken.ID)
+}
+
+// ValidateAccessToken checks an access token presented to the API as a bearer token
+func (s *OAuthService) ValidateAccessToken(ctx context.Context, token string) (*model.OAuthToken, error) {
+	oauthToken, err := s.tokenRepo.FindByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	if oauthToken.TokenType != model.OAuthTokenTypeAccess || !oauthToken.IsActive() || oauthToken.Client.ID == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	return oauthToken, nil
+}
+
+// verifyCodeChallenge checks the PKCE verifier against the S256 challenge (RFC 7636 section 4.6)
+func verifyCodeChallenge(challenge, verifier string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	return securetoken.Equal(base64.RawURLEncoding.EncodeToString(sum[:]), challenge)
+}
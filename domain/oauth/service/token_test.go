@@ -0,0 +1,64 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+// codeChallenge derives the S256 challenge a client sends for verifier
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func TestVerifyCodeChallenge(t *testing.T) {
+	// Example from RFC 7636 appendix B
+	const (
+		verifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+		challenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	)
+
+	tests := []struct {
+		name      string
+		challenge string
+		verifier  string
+		want      bool
+	}{
+		{name: "matching verifier", challenge: challenge, verifier: verifier, want: true},
+		{name: "other verifier", challenge: challenge, verifier: strings.Repeat("a", 43), want: false},
+		{name: "plain challenge", challenge: verifier, verifier: verifier, want: false},
+		{name: "padded challenge", challenge: challenge + "=", verifier: verifier, want: false},
+		{name: "empty challenge", challenge: "", verifier: verifier, want: false},
+		{name: "empty verifier", challenge: challenge, verifier: "", want: false},
+		{name: "verifier too short", challenge: challenge, verifier: verifier[:42], want: false},
+		{name: "verifier too long", challenge: challenge, verifier: strings.Repeat("a", 129), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := verifyCodeChallenge(tt.challenge, tt.verifier); got != tt.want {
+				t.Fatalf("verifyCodeChallenge(%q, %q) = %v, want %v", tt.challenge, tt.verifier, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyCodeChallengeLengthBounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		verifier string
+	}{
+		{name: "shortest", verifier: strings.Repeat("a", 43)},
+		{name: "longest", verifier: strings.Repeat("a", 128)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !verifyCodeChallenge(codeChallenge(tt.verifier), tt.verifier) {
+				t.Fatalf("a verifier of %d characters should be accepted", len(tt.verifier))
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package entity
+
+// CreateOrganizationRequest represents the request to create an organization, the creator becomes its owner
+type CreateOrganizationRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=255"`
+	Slug string `json:"slug" validate:"required,min=2,max=100"`
+}
+
+// UpdateOrganizationRequest represents the request to rename an organization
+type UpdateOrganizationRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=255"`
+}
+
+// AddMemberRequest represents the request to add an existing user to an organization
+type AddMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+// UpdateMemberRequest represents the request to change the organization role of a member
+type UpdateMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner admin member"`
+}
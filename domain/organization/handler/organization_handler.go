@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"errors"
+	"go-api/app"
+	"go-api/domain/organization/entity"
+	"go-api/domain/organization/service"
+	"go-api/model"
+	"go-api/shared/logger"
+	"go-api/shared/response"
+	"go-api/shared/validator"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OrganizationHandler serves the organization and membership endpoints
+type OrganizationHandler struct {
+	OrganizationService *service.OrganizationService
+}
+
+func NewOrganizationHandler(p *app.Provider) *OrganizationHandler {
+	return &OrganizationHandler{
+		OrganizationService: service.NewOrganizationService(p),
+	}
+}
+
+func (h *OrganizationHandler) ListOrganizations(c *fiber.Ctx) error {
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	ctx := c.UserContext()
+
+	organizations, err := h.OrganizationService.ListOrganizations(ctx, userID.(uint))
+	if err != nil {
+		return response.InternalServerError(c, err, "Failed to retrieve organizations")
+	}
+
+	return response.Success(c, organizations, "Organizations retrieved successfully")
+}
+
+func (h *OrganizationHandler) CreateOrganization(c *fiber.Ctx) error {
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	var req entity.CreateOrganizationRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	organization, err := h.OrganizationService.CreateOrganization(ctx, userID.(uint), &req)
+	if err != nil {
+		return organizationErrorResponse(c, err, "Failed to create organization")
+	}
+
+	logger.Infof("Organization %s created by user %v", organization.Slug, userID)
+
+	return response.Created(c, organization, "Organization created successfully")
+}
+
+func (h *OrganizationHandler) GetOrganization(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	organization, err := h.OrganizationService.GetOrganization(ctx, c.Locals("organization_id").(uint))
+	if err != nil {
+		return organizationErrorResponse(c, err, "Failed to retrieve organization")
+	}
+
+	return response.Success(c, organization, "Organization retrieved successfully")
+}
+
+func (h *OrganizationHandler) UpdateOrganization(c *fiber.Ctx) error {
+	var req entity.UpdateOrganizationRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	organization, err := h.OrganizationService.UpdateOrganization(ctx, c.Locals("organization_id").(uint), &req)
+	if err != nil {
+		return organizationErrorResponse(c, err, "Failed to update organization")
+	}
+
+	return response.Success(c, organization, "Organization updated successfully")
+}
+
+func (h *OrganizationHandler) DeleteOrganization(c *fiber.Ctx) error {
+	organizationID := c.Locals("organization_id").(uint)
+	ctx := c.UserContext()
+
+	if err := h.OrganizationService.DeleteOrganization(ctx, organizationID); err != nil {
+		return organizationErrorResponse(c, err, "Failed to delete organization")
+	}
+
+	logger.Infof("Organization %d deleted by user %v", organizationID, c.Locals("user_id"))
+
+	return response.Success(c, nil, "Organization deleted successfully")
+}
+
+func (h *OrganizationHandler) ListMembers(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	members, err := h.OrganizationService.ListMembers(ctx)
+	if err != nil {
+		return response.InternalServerError(c, err, "Failed to retrieve members")
+	}
+
+	return response.Success(c, members, "Members retrieved successfully")
+}
+
+func (h *OrganizationHandler) AddMember(c *fiber.Ctx) error {
+	var req entity.AddMemberRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	actor := c.Locals("organization_member").(*model.OrganizationMember)
+	ctx := c.UserContext()
+
+	member, err := h.OrganizationService.AddMember(ctx, actor, &req)
+	if err != nil {
+		return organizationErrorResponse(c, err, "Failed to add member")
+	}
+
+	logger.Infof("User %d added to organization %d as %s by user %d", member.UserID, member.OrganizationID, member.Role, actor.UserID)
+
+	return response.Created(c, member, "Member added successfully")
+}
+
+func (h *OrganizationHandler) UpdateMember(c *fiber.Ctx) error {
+	userID, err := strconv.ParseUint(c.Params("userID"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid user ID")
+	}
+
+	var req entity.UpdateMemberRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	actor := c.Locals("organization_member").(*model.OrganizationMember)
+	ctx := c.UserContext()
+
+	member, err := h.OrganizationService.UpdateMemberRole(ctx, actor, uint(userID), req.Role)
+	if err != nil {
+		return organizationErrorResponse(c, err, "Failed to update member")
+	}
+
+	logger.Infof("Role of user %d in organization %d set to %s by user %d", member.UserID, member.OrganizationID, member.Role, actor.UserID)
+
+	return response.Success(c, member, "Member updated successfully")
+}
+
+func (h *OrganizationHandler) RemoveMember(c *fiber.Ctx) error {
+	userID, err := strconv.ParseUint(c.Params("userID"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid user ID")
+	}
+
+	actor := c.Locals("organization_member").(*model.OrganizationMember)
+	ctx := c.UserContext()
+
+	if err := h.OrganizationService.RemoveMember(ctx, actor, uint(userID)); err != nil {
+		return organizationErrorResponse(c, err, "Failed to remove member")
+	}
+
+	logger.Infof("User %d removed from organization %d by user %d", userID, actor.OrganizationID, actor.UserID)
+
+	return response.Success(c, nil, "Member removed successfully")
+}
+
+// organizationErrorResponse maps the errors of the organization service to a response
+func organizationErrorResponse(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrOrganizationNotFound):
+		return response.NotFound(c, "Organization not found")
+	case errors.Is(err, service.ErrMemberNotFound):
+		return response.NotFound(c, "Member not found")
+	case errors.Is(err, service.ErrInsufficientRole),
+		errors.Is(err, service.ErrOwnerRequired):
+		return response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrSlugTaken),
+		errors.Is(err, service.ErrAlreadyMember),
+		errors.Is(err, service.ErrLastOwner):
+		return response.Error(c, fiber.StatusConflict, err, message)
+	case errors.Is(err, service.ErrInvalidSlug),
+		errors.Is(err, service.ErrUserNotFound):
+		return response.BadRequest(c, err, message)
+	default:
+		return response.InternalServerError(c, err, message)
+	}
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-api/app"
+	"go-api/domain/organization/entity"
+	"go-api/model"
+	"go-api/repository"
+	"go-api/shared/constant"
+	"go-api/tenant"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+var (
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrInvalidSlug          = errors.New("slug must contain only lowercase letters, digits and single hyphens")
+	ErrSlugTaken            = errors.New("slug is already in use")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrAlreadyMember        = errors.New("user is already a member of this organization")
+	ErrMemberNotFound       = errors.New("member not found")
+	ErrInsufficientRole     = errors.New("your organization role does not allow this action")
+	ErrOwnerRequired        = errors.New("only owners can grant, change or remove the owner role")
+	ErrLastOwner            = errors.New("an organization must keep at least one owner")
+)
+
+// OrganizationService manages organizations and their members. Member methods work on the active
+// organization of ctx, set by the ResolveOrganization middleware.
+type OrganizationService struct {
+	provider         *app.Provider
+	organizationRepo *repository.OrganizationRepository
+	memberRepo       *repository.OrganizationMemberRepository
+	userRepo         *repository.UserRepository
+}
+
+func NewOrganizationService(p *app.Provider) *OrganizationService {
+	return &OrganizationService{
+		provider:         p,
+		organizationRepo: repository.NewOrganizationRepository(p.DB),
+		memberRepo:       repository.NewOrganizationMemberRepository(p.DB),
+		userRepo:         repository.NewUserRepository(p.DB),
+	}
+}
+
+// ListOrganizations returns the organizations the user is a member of
+func (s *OrganizationService) ListOrganizations(ctx context.Context, userID uint) ([]model.Organization, error) {
+	return s.organizationRepo.ListForUser(ctx, userID)
+}
+
+// CreateOrganization creates an organization owned by the user
+func (s *OrganizationService) CreateOrganization(ctx context.Context, userID uint, req *entity.CreateOrganizationRequest) (*model.Organization, error) {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !slugPattern.MatchString(slug) {
+		return nil, ErrInvalidSlug
+	}
+
+	exists, err := s.organizationRepo.SlugExists(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrSlugTaken
+	}
+
+	organization := &model.Organization{
+		Name: strings.TrimSpace(req.Name),
+		Slug: slug,
+	}
+
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewOrganizationRepository(tx).Create(ctx, organization); err != nil {
+			return err
+		}
+		// The owner membership is the first row of the new tenant
+		return repository.NewOrganizationMemberRepository(tx).Create(tenant.WithOrganization(ctx, organization.ID), &model.OrganizationMember{
+			UserID: userID,
+			Role:   constant.OrganizationRoleOwner,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return organization, nil
+}
+
+func (s *OrganizationService) GetOrganization(ctx context.Context, id uint) (*model.Organization, error) {
+	organization, err := s.organizationRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return organization, nil
+}
+
+func (s *OrganizationService) UpdateOrganization(ctx context.Context, id uint, req *entity.UpdateOrganizationRequest) (*model.Organization, error) {
+	if err := s.organizationRepo.Update(ctx, id, map[string]interface{}{"name": strings.TrimSpace(req.Name)}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+
+	return s.GetOrganization(ctx, id)
+}
+
+// DeleteOrganization permanently deletes an organization and every membership
+func (s *OrganizationService) DeleteOrganization(ctx context.Context, id uint) error {
+	if err := s.organizationRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOrganizationNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ListMembers returns the members of the active organization
+func (s *OrganizationService) ListMembers(ctx context.Context) ([]model.OrganizationMember, error) {
+	return s.memberRepo.List(ctx)
+}
+
+// AddMember adds an existing user to the active organization
+func (s *OrganizationService) AddMember(ctx context.Context, actor *model.OrganizationMember, req *entity.AddMemberRequest) (*model.OrganizationMember, error) {
+	if req.Role == constant.OrganizationRoleOwner && actor.Role != constant.OrganizationRoleOwner {
+		return nil, ErrOwnerRequired
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, strings.TrimSpace(req.Email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if _, err := s.memberRepo.FindByUserID(ctx, user.ID); err == nil {
+		return nil, ErrAlreadyMember
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	member := &model.OrganizationMember{
+		UserID: user.ID,
+		Role:   req.Role,
+	}
+	if err := s.memberRepo.Create(ctx, member); err != nil {
+		return nil, err
+	}
+
+	return s.findMember(ctx, user.ID)
+}
+
+// UpdateMemberRole changes the organization role of a member. Only owners can touch the owner role.
+func (s *OrganizationService) UpdateMemberRole(ctx context.Context, actor *model.OrganizationMember, userID uint, role string) (*model.OrganizationMember, error) {
+	member, err := s.findMember(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role == role {
+		return member, nil
+	}
+
+	if (member.Role == constant.OrganizationRoleOwner || role == constant.OrganizationRoleOwner) && actor.Role != constant.OrganizationRoleOwner {
+		return nil, ErrOwnerRequired
+	}
+
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		memberRepo := repository.NewOrganizationMemberRepository(tx)
+		if member.Role == constant.OrganizationRoleOwner {
+			if err := ensureAnotherOwner(ctx, memberRepo); err != nil {
+				return err
+			}
+		}
+		if err := memberRepo.UpdateRole(ctx, userID, role); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMemberNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findMember(ctx, userID)
+}
+
+// RemoveMember removes a user from the active organization. Members may always leave, removing
+// someone else requires managing the organization.
+func (s *OrganizationService) RemoveMember(ctx context.Context, actor *model.OrganizationMember, userID uint) error {
+	if userID != actor.UserID && !actor.CanManage() {
+		return ErrInsufficientRole
+	}
+
+	member, err := s.findMember(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == constant.OrganizationRoleOwner && userID != actor.UserID && actor.Role != constant.OrganizationRoleOwner {
+		return ErrOwnerRequired
+	}
+
+	return s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		memberRepo := repository.NewOrganizationMemberRepository(tx)
+		if member.Role == constant.OrganizationRoleOwner {
+			if err := ensureAnotherOwner(ctx, memberRepo); err != nil {
+				return err
+			}
+		}
+		if err := memberRepo.Delete(ctx, userID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMemberNotFound
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+func (s *OrganizationService) findMember(ctx context.Context, userID uint) (*model.OrganizationMember, error) {
+	member, err := s.memberRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+// ensureAnotherOwner fails when the active organization has a single owner, which is about to lose the role.
+// The owner rows stay locked until the transaction ends, so two owners cannot demote or remove each other at once.
+func ensureAnotherOwner(ctx context.Context, memberRepo *repository.OrganizationMemberRepository) error {
+	owners, err := memberRepo.CountByRoleForUpdate(ctx, constant.OrganizationRoleOwner)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+package entity
+
+// AllowedActionsResponse represents the actions the current user may perform on a resource
+type AllowedActionsResponse struct {
+	Resource string   `json:"resource"`
+	ID       uint     `json:"id"`
+	Actions  []string `json:"actions"`
+}
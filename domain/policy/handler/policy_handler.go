@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"errors"
+	"go-api/app"
+	"go-api/domain/policy/entity"
+	"go-api/model"
+	"go-api/policy"
+	"go-api/shared/response"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PolicyHandler reports what the current user is allowed to do, so clients can hide what they cannot use
+type PolicyHandler struct {
+	policies *policy.Registry
+}
+
+func NewPolicyHandler(p *app.Provider) *PolicyHandler {
+	return &PolicyHandler{
+		policies: p.Policies,
+	}
+}
+
+func (h *PolicyHandler) AllowedActions(c *fiber.Ctx) error {
+	actor, ok := c.Locals("user").(model.User)
+	if !ok || actor.ID == 0 {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid resource ID")
+	}
+
+	resource := c.Params("resource")
+	ctx := c.UserContext()
+
+	actions, err := h.policies.AllowedActions(ctx, &actor, resource, uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, policy.ErrUnknownResource):
+			return response.NotFound(c, "Unknown resource type")
+		// Nothing is allowed on a missing resource, answering the same as for a forbidden one
+		// keeps users from probing which IDs exist
+		case errors.Is(err, policy.ErrResourceNotFound):
+			actions = []string{}
+		default:
+			return response.InternalServerError(c, err, "Failed to resolve allowed actions")
+		}
+	}
+
+	return response.Success(c, entity.AllowedActionsResponse{
+		Resource: resource,
+		ID:       uint(id),
+		Actions:  actions,
+	}, "Allowed actions retrieved successfully")
+}
@@ -0,0 +1,24 @@
+package entity
+
+// CreateRoleRequest represents the request to create a role.
+// Codes are stored uppercase and cannot be changed afterwards.
+type CreateRoleRequest struct {
+	Code        string   `json:"code" validate:"required,min=2,max=50"`
+	Name        string   `json:"name" validate:"required,min=2,max=255"`
+	Permissions []string `json:"permissions"`
+}
+
+// UpdateRoleRequest represents the request to rename a role
+type UpdateRoleRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=255"`
+}
+
+// SetRolePermissionsRequest represents the full list of permissions granted to a role
+type SetRolePermissionsRequest struct {
+	Permissions []string `json:"permissions"`
+}
+
+// AssignRoleRequest represents the request to change the role of a user
+type AssignRoleRequest struct {
+	RoleID uint `json:"role_id" validate:"required"`
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"errors"
+	"go-api/app"
+	"go-api/domain/role/entity"
+	"go-api/domain/role/service"
+	userservice "go-api/domain/user/service"
+	"go-api/model"
+	"go-api/policy"
+	"go-api/shared/logger"
+	"go-api/shared/response"
+	"go-api/shared/validator"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RoleHandler serves the admin role and permission management endpoints
+type RoleHandler struct {
+	RoleService *service.RoleService
+	policies    *policy.Registry
+}
+
+func NewRoleHandler(p *app.Provider) *RoleHandler {
+	return &RoleHandler{
+		RoleService: service.NewRoleService(p),
+		policies:    p.Policies,
+	}
+}
+
+func (h *RoleHandler) ListRoles(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	roles, err := h.RoleService.ListRoles(ctx)
+	if err != nil {
+		return response.InternalServerError(c, err, "Failed to retrieve roles")
+	}
+
+	return response.Success(c, roles, "Roles retrieved successfully")
+}
+
+func (h *RoleHandler) ListPermissions(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	permissions, err := h.RoleService.ListPermissions(ctx)
+	if err != nil {
+		return response.InternalServerError(c, err, "Failed to retrieve permissions")
+	}
+
+	return response.Success(c, permissions, "Permissions retrieved successfully")
+}
+
+func (h *RoleHandler) GetRole(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid role ID")
+	}
+
+	ctx := c.UserContext()
+
+	role, err := h.RoleService.GetRole(ctx, uint(id))
+	if err != nil {
+		return roleErrorResponse(c, err, "Failed to retrieve role")
+	}
+
+	return response.Success(c, role, "Role retrieved successfully")
+}
+
+func (h *RoleHandler) CreateRole(c *fiber.Ctx) error {
+	adminID := c.Locals("user_id")
+	if adminID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	var req entity.CreateRoleRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	role, err := h.RoleService.CreateRole(ctx, &req)
+	if err != nil {
+		return roleErrorResponse(c, err, "Failed to create role")
+	}
+
+	logger.Infof("Role %s created by admin %v", role.Code, adminID)
+
+	return response.Created(c, role, "Role created successfully")
+}
+
+func (h *RoleHandler) UpdateRole(c *fiber.Ctx) error {
+	adminID := c.Locals("user_id")
+	if adminID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid role ID")
+	}
+
+	var req entity.UpdateRoleRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	role, err := h.RoleService.UpdateRole(ctx, uint(id), &req)
+	if err != nil {
+		return roleErrorResponse(c, err, "Failed to update role")
+	}
+
+	logger.Infof("Role %s updated by admin %v", role.Code, adminID)
+
+	return response.Success(c, role, "Role updated successfully")
+}
+
+func (h *RoleHandler) SetRolePermissions(c *fiber.Ctx) error {
+	adminID := c.Locals("user_id")
+	if adminID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid role ID")
+	}
+
+	var req entity.SetRolePermissionsRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	ctx := c.UserContext()
+
+	admin := c.Locals("user").(model.User)
+	role, err := h.RoleService.SetRolePermissions(ctx, &admin, uint(id), req.Permissions)
+	if err != nil {
+		return roleErrorResponse(c, err, "Failed to update role permissions")
+	}
+
+	logger.Infof("Permissions of role %s set to %v by admin %v", role.Code, req.Permissions, adminID)
+
+	return response.Success(c, role, "Role permissions updated successfully")
+}
+
+func (h *RoleHandler) DeleteRole(c *fiber.Ctx) error {
+	adminID := c.Locals("user_id")
+	if adminID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid role ID")
+	}
+
+	ctx := c.UserContext()
+
+	if err := h.RoleService.DeleteRole(ctx, uint(id)); err != nil {
+		return roleErrorResponse(c, err, "Failed to delete role")
+	}
+
+	logger.Infof("Role %d deleted by admin %v", id, adminID)
+
+	return response.Success(c, nil, "Role deleted successfully")
+}
+
+func (h *RoleHandler) AssignRole(c *fiber.Ctx) error {
+	adminID := c.Locals("user_id")
+	if adminID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid user ID")
+	}
+
+	var req entity.AssignRoleRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	target, err := h.RoleService.GetUser(ctx, uint(id))
+	if err != nil {
+		return roleErrorResponse(c, err, "Failed to change user role")
+	}
+	if ok, err := h.policies.Authorize(c, userservice.ActionUserAssignRole, target); !ok {
+		return err
+	}
+
+	admin := c.Locals("user").(model.User)
+	user, err := h.RoleService.AssignRole(ctx, &admin, uint(id), req.RoleID)
+	if err != nil {
+		return roleErrorResponse(c, err, "Failed to change user role")
+	}
+
+	logger.Infof("Role of user %d set to %s by admin %v", user.ID, user.Role.Code, adminID)
+
+	return response.Success(c, user, "User role updated successfully")
+}
+
+// roleErrorResponse maps the errors of the role service to a response
+func roleErrorResponse(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrRoleNotFound):
+		return response.NotFound(c, "Role not found")
+	case errors.Is(err, service.ErrUserNotFound):
+		return response.NotFound(c, "User not found")
+	case errors.Is(err, service.ErrSystemRole),
+		errors.Is(err, service.ErrCannotModifySelf),
+		errors.Is(err, service.ErrPrivilegeEscalation):
+		return response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrRoleInUse):
+		return response.Error(c, fiber.StatusConflict, err, message)
+	case errors.Is(err, service.ErrInvalidRoleCode),
+		errors.Is(err, service.ErrRoleCodeTaken),
+		errors.Is(err, service.ErrAdminPermissions),
+		errors.Is(err, service.ErrUnknownPermission):
+		return response.BadRequest(c, err, message)
+	default:
+		return response.InternalServerError(c, err, message)
+	}
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-api/app"
+	"go-api/model"
+	"go-api/permission"
+	"go-api/policy"
+	"go-api/repository"
+	"go-api/shared/constant"
+)
+
+const ResourceRole = "role"
+
+// Actions on roles
+const (
+	ActionRoleView           = "role.view"
+	ActionRoleUpdate         = "role.update"
+	ActionRoleSetPermissions = "role.set_permissions"
+	ActionRoleDelete         = "role.delete"
+)
+
+// RegisterPolicies registers the rules for roles. The permissions of the ADMIN role are fixed
+// and system roles cannot be deleted, whatever the permissions of the actor.
+func RegisterPolicies(p *app.Provider) {
+	roleRepo := repository.NewRoleRepository(p.DB)
+	p.Policies.RegisterResource(ResourceRole, func(ctx context.Context, id uint) (any, error) {
+		role, err := roleRepo.FindByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, repository.ErrRoleNotFound) {
+				return nil, policy.ErrResourceNotFound
+			}
+			return nil, err
+		}
+		return role, nil
+	})
+
+	p.Policies.Register(ResourceRole, ActionRoleView, policy.For(func(ctx context.Context, actor *model.User, role *model.Role) (bool, error) {
+		return p.Permissions.HasAll(ctx, actor, permission.RolesRead)
+	}))
+	p.Policies.Register(ResourceRole, ActionRoleUpdate, policy.For(func(ctx context.Context, actor *model.User, role *model.Role) (bool, error) {
+		return p.Permissions.HasAll(ctx, actor, permission.RolesManage)
+	}))
+	p.Policies.Register(ResourceRole, ActionRoleSetPermissions, policy.For(func(ctx context.Context, actor *model.User, role *model.Role) (bool, error) {
+		if role.Code == constant.RoleCodeAdmin {
+			return false, nil
+		}
+		return p.Permissions.HasAll(ctx, actor, permission.RolesManage)
+	}))
+	p.Policies.Register(ResourceRole, ActionRoleDelete, policy.For(func(ctx context.Context, actor *model.User, role *model.Role) (bool, error) {
+		if role.Code == constant.RoleCodeAdmin || role.Code == constant.RoleCodeUser {
+			return false, nil
+		}
+		return p.Permissions.HasAll(ctx, actor, permission.RolesManage)
+	}))
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api/app"
+	"go-api/domain/role/entity"
+	"go-api/model"
+	"go-api/permission"
+	"go-api/repository"
+	"go-api/shared/constant"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var roleCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+var (
+	ErrRoleNotFound        = errors.New("role not found")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrInvalidRoleCode     = errors.New("role code must start with a letter and contain only letters, digits and underscores")
+	ErrRoleCodeTaken       = errors.New("role code is already in use")
+	ErrSystemRole          = errors.New("the ADMIN and USER roles cannot be deleted")
+	ErrAdminPermissions    = errors.New("the ADMIN role always holds every permission")
+	ErrRoleInUse           = errors.New("role is still assigned to users")
+	ErrUnknownPermission   = errors.New("unknown permission")
+	ErrCannotModifySelf    = errors.New("admins cannot change their own role")
+	ErrPrivilegeEscalation = errors.New("you cannot grant permissions or roles you do not hold")
+)
+
+// RoleService lets admins manage roles, the permissions granted to them and the role of users
+type RoleService struct {
+	provider       *app.Provider
+	roleRepo       *repository.RoleRepository
+	permissionRepo *repository.PermissionRepository
+	userRepo       *repository.UserRepository
+}
+
+func NewRoleService(p *app.Provider) *RoleService {
+	return &RoleService{
+		provider:       p,
+		roleRepo:       repository.NewRoleRepository(p.DB),
+		permissionRepo: repository.NewPermissionRepository(p.DB),
+		userRepo:       repository.NewUserRepository(p.DB),
+	}
+}
+
+func (s *RoleService) ListRoles(ctx context.Context) ([]model.Role, error) {
+	return s.roleRepo.List(ctx)
+}
+
+// ListPermissions returns every permission that can be granted to a role
+func (s *RoleService) ListPermissions(ctx context.Context) ([]model.Permission, error) {
+	return s.permissionRepo.FindAll(ctx)
+}
+
+func (s *RoleService) GetRole(ctx context.Context, id uint) (*model.Role, error) {
+	role, err := s.roleRepo.FindByIDWithPermissions(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrRoleNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+// CreateRole creates a role and grants it the given permissions
+func (s *RoleService) CreateRole(ctx context.Context, req *entity.CreateRoleRequest) (*model.Role, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	if !roleCodePattern.MatchString(code) {
+		return nil, ErrInvalidRoleCode
+	}
+
+	exists, err := s.roleRepo.CodeExists(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrRoleCodeTaken
+	}
+
+	permissions, err := s.findPermissions(ctx, req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &model.Role{
+		Code: code,
+		Name: strings.TrimSpace(req.Name),
+	}
+
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		roleRepo := repository.NewRoleRepository(tx)
+		if err := roleRepo.Create(ctx, role); err != nil {
+			return err
+		}
+		if len(permissions) == 0 {
+			return nil
+		}
+		return roleRepo.ReplacePermissions(ctx, role, permissions)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetRole(ctx, role.ID)
+}
+
+// UpdateRole renames a role, its code never changes
+func (s *RoleService) UpdateRole(ctx context.Context, id uint, req *entity.UpdateRoleRequest) (*model.Role, error) {
+	if err := s.roleRepo.Update(ctx, id, map[string]interface{}{"name": strings.TrimSpace(req.Name)}); err != nil {
+		if errors.Is(err, repository.ErrRoleNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+
+	return s.GetRole(ctx, id)
+}
+
+// SetRolePermissions replaces the permissions granted to a role
+func (s *RoleService) SetRolePermissions(ctx context.Context, actor *model.User, id uint, codes []string) (*model.Role, error) {
+	role, err := s.GetRole(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if role.Code == constant.RoleCodeAdmin {
+		return nil, ErrAdminPermissions
+	}
+
+	permissions, err := s.findPermissions(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only permissions the actor holds can be added, so nobody extends their own reach through a role
+	var added []string
+	for _, granted := range permissions {
+		if !slices.ContainsFunc(role.Permissions, func(p model.Permission) bool { return p.ID == granted.ID }) {
+			added = append(added, granted.Code)
+		}
+	}
+	holds, err := s.provider.Permissions.HasAll(ctx, actor, added...)
+	if err != nil {
+		return nil, err
+	}
+	if !holds {
+		return nil, ErrPrivilegeEscalation
+	}
+
+	if err := s.roleRepo.ReplacePermissions(ctx, role, permissions); err != nil {
+		return nil, err
+	}
+	s.provider.Permissions.Invalidate(role.ID)
+
+	return s.GetRole(ctx, id)
+}
+
+// DeleteRole deletes a role that is no longer assigned to any user. System roles cannot be deleted.
+func (s *RoleService) DeleteRole(ctx context.Context, id uint) error {
+	role, err := s.GetRole(ctx, id)
+	if err != nil {
+		return err
+	}
+	if role.Code == constant.RoleCodeAdmin || role.Code == constant.RoleCodeUser {
+		return ErrSystemRole
+	}
+
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		roleRepo := repository.NewRoleRepository(tx)
+		count, err := roleRepo.CountUsers(ctx, id)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrRoleInUse
+		}
+		if err := roleRepo.Delete(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrRoleNotFound) {
+				return ErrRoleNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.provider.Permissions.Invalidate(id)
+	return nil
+}
+
+// AssignRole changes the role of a user and signs them out everywhere, so no token keeps the old role
+func (s *RoleService) AssignRole(ctx context.Context, actor *model.User, userID, roleID uint) (*model.User, error) {
+	// Keeps at least the acting admin able to manage roles
+	if userID == actor.ID {
+		return nil, ErrCannotModifySelf
+	}
+
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.roleRepo.FindByID(ctx, roleID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRoleNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+
+	if user.RoleID == role.ID {
+		return user, nil
+	}
+
+	covered, err := s.provider.Permissions.CoversRole(ctx, actor, role)
+	if err != nil {
+		return nil, err
+	}
+	if !covered {
+		return nil, ErrPrivilegeEscalation
+	}
+
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewUserRepository(tx).Update(ctx, userID, map[string]interface{}{"role_id": role.ID}); err != nil {
+			return err
+		}
+		if err := repository.NewRefreshTokenRepository(tx).RevokeAllUserTokens(ctx, userID); err != nil {
+			return err
+		}
+		return s.provider.Tokens.WithDB(tx).RevokeAllUserTokens(ctx, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findUser(ctx, userID)
+}
+
+// findPermissions loads the permissions with the given codes, every code must be registered
+func (s *RoleService) findPermissions(ctx context.Context, codes []string) ([]model.Permission, error) {
+	codes = slices.Compact(slices.Sorted(slices.Values(codes)))
+	for _, code := range codes {
+		if !permission.IsRegistered(code) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPermission, code)
+		}
+	}
+
+	permissions, err := s.permissionRepo.FindByCodes(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+	// Registered permissions are stored on boot, a mismatch means the registry is out of sync
+	if len(permissions) != len(codes) {
+		return nil, fmt.Errorf("permissions %v are registered but not stored", codes)
+	}
+
+	return permissions, nil
+}
+
+// GetUser returns the user whose role is about to change
+func (s *RoleService) GetUser(ctx context.Context, id uint) (*model.User, error) {
+	return s.findUser(ctx, id)
+}
+
+func (s *RoleService) findUser(ctx context.Context, id uint) (*model.User, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
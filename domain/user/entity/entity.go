@@ -0,0 +1,37 @@
+package entity
+
+import "go-api/model"
+
+// ListUsersRequest represents the filters and pagination of the user list
+type ListUsersRequest struct {
+	Search  string `query:"search" validate:"omitempty,max=100"`
+	RoleID  uint   `query:"role_id"`
+	Page    int    `query:"page" validate:"omitempty,min=1"`
+	PerPage int    `query:"per_page" validate:"omitempty,min=1,max=100"`
+}
+
+// CreateUserRequest represents the request of an admin to create a user.
+// The USER role is assigned when no role is given.
+type CreateUserRequest struct {
+	Name          string `json:"name" validate:"required,min=2,max=100"`
+	Email         string `json:"email" validate:"required,email"`
+	Password      string `json:"password" validate:"required"`
+	RoleID        uint   `json:"role_id"`
+	EmailVerified bool   `json:"email_verified"`
+}
+
+// UpdateUserRequest represents the request of an admin to update a user, only the given fields change
+type UpdateUserRequest struct {
+	Name     *string `json:"name" validate:"omitempty,min=2,max=100"`
+	Email    *string `json:"email" validate:"omitempty,email"`
+	Password *string `json:"password"`
+	RoleID   *uint   `json:"role_id"`
+}
+
+// UserListResponse represents a page of users
+type UserListResponse struct {
+	Users   []model.User `json:"users"`
+	Total   int64        `json:"total"`
+	Page    int          `json:"page"`
+	PerPage int          `json:"per_page"`
+}
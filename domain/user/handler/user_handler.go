@@ -1,96 +1,228 @@
 package handler
 
 import (
-	"strconv"
-
+	"errors"
+	"go-api/app"
+	"go-api/domain/user/entity"
 	"go-api/domain/user/service"
 	"go-api/model"
+	"go-api/passwordhash"
+	"go-api/policy"
+	"go-api/shared/logger"
+	"go-api/shared/response"
+	"go-api/shared/validator"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
-	"gorm.io/gorm"
 )
 
-// UserHandler demonstrates how to create handlers with proper dependency injection
+// UserHandler serves the admin user management endpoints
 type UserHandler struct {
-	userService *service.UserService
+	UserService *service.UserService
+	policies    *policy.Registry
 }
 
-// NewUserHandler creates a new user handler with proper dependency injection
-func NewUserHandler(db *gorm.DB) *UserHandler {
+func NewUserHandler(p *app.Provider) *UserHandler {
 	return &UserHandler{
-		userService: service.NewUserService(db),
+		UserService: service.NewUserService(p),
+		policies:    p.Policies,
 	}
 }
 
-// GetUser retrieves a user by ID
+func (h *UserHandler) ListUsers(c *fiber.Ctx) error {
+	var req entity.ListUsersRequest
+	if err := c.QueryParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid query parameters")
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	users, err := h.UserService.ListUsers(ctx, &req)
+	if err != nil {
+		return response.InternalServerError(c, err, "Failed to retrieve users")
+	}
+
+	return response.Success(c, users, "Users retrieved successfully")
+}
+
 func (h *UserHandler) GetUser(c *fiber.Ctx) error {
-	idStr := c.Params("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid user ID",
-		})
+		return response.BadRequest(c, err, "Invalid user ID")
 	}
 
-	user, err := h.userService.GetUserByID(c.Context(), uint(id))
+	ctx := c.UserContext()
+
+	user, err := h.UserService.GetUser(ctx, uint(id))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "User not found",
-		})
+		if errors.Is(err, service.ErrUserNotFound) {
+			return response.NotFound(c, "User not found")
+		}
+		return response.InternalServerError(c, err, "Failed to retrieve user")
+	}
+	if ok, err := h.policies.Authorize(c, service.ActionUserView, user); !ok {
+		return err
 	}
 
-	return c.JSON(fiber.Map{
-		"data": user,
-	})
+	return response.Success(c, user, "User retrieved successfully")
 }
 
-// CreateUser creates a new user
 func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
-	var user model.User
-	if err := c.BodyParser(&user); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body",
-		})
+	adminID := c.Locals("user_id")
+	if adminID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	var req entity.CreateUserRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request structure
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	// Additional password validation
+	if passwordErrors := validator.ValidatePasswordWithDetails(req.Password, req.Email, req.Name); passwordErrors != nil {
+		return response.ValidationError(c, passwordErrors)
+	}
+
+	ctx := c.UserContext()
+
+	// Choosing the role is guarded like changing it, a new user holds no role yet
+	if req.RoleID != 0 {
+		if ok, err := h.policies.Authorize(c, service.ActionUserAssignRole, &model.User{}); !ok {
+			return err
+		}
+	}
+
+	admin := c.Locals("user").(model.User)
+	user, err := h.UserService.CreateUser(ctx, &admin, &req)
+	if err != nil {
+		return userErrorResponse(c, err, "Failed to create user")
+	}
+
+	logger.Infof("User %d created by admin %v", user.ID, adminID)
+
+	return response.Created(c, user, "User created successfully")
+}
+
+func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
+	adminID := c.Locals("user_id")
+	if adminID == nil {
+		return response.Unauthorized(c, "Unauthorized")
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return response.BadRequest(c, err, "Invalid user ID")
+	}
+
+	var req entity.UpdateUserRequest
+	// Parse JSON body
+	if err := c.BodyParser(&req); err != nil {
+		return response.BadRequest(c, err, "Invalid request body")
+	}
+
+	// Validate request structure
+	if validationErrors := validator.ValidateStruct(&req); validationErrors != nil {
+		return response.ValidationError(c, validationErrors)
+	}
+
+	ctx := c.UserContext()
+
+	target, err := h.UserService.GetUser(ctx, uint(id))
+	if err != nil {
+		return userErrorResponse(c, err, "Failed to update user")
+	}
+
+	if ok, err := h.policies.Authorize(c, service.ActionUserUpdate, target); !ok {
+		return err
+	}
+	// Changing the role is guarded like the dedicated role endpoint
+	if req.RoleID != nil {
+		if ok, err := h.policies.Authorize(c, service.ActionUserAssignRole, target); !ok {
+			return err
+		}
+	}
+
+	// Additional password validation, against the new details or the stored ones they replace
+	if req.Password != nil {
+		email, name := target.Email, target.Name
+		if req.Email != nil {
+			email = *req.Email
+		}
+		if req.Name != nil {
+			name = *req.Name
+		}
+		if passwordErrors := validator.ValidatePasswordWithDetails(*req.Password, email, name); passwordErrors != nil {
+			return response.ValidationError(c, passwordErrors)
+		}
 	}
 
-	if err := h.userService.CreateUser(c.Context(), &user); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+	admin := c.Locals("user").(model.User)
+	user, err := h.UserService.UpdateUser(ctx, &admin, uint(id), &req)
+	if err != nil {
+		return userErrorResponse(c, err, "Failed to update user")
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"data": user,
-	})
+	logger.Infof("User %d updated by admin %v", id, adminID)
+
+	return response.Success(c, user, "User updated successfully")
 }
 
-// GetUserByEmail retrieves a user by email
-func (h *UserHandler) GetUserByEmail(c *fiber.Ctx) error {
-	email := c.Query("email")
-	if email == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Email parameter is required",
-		})
+func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
+	adminID := c.Locals("user_id")
+	if adminID == nil {
+		return response.Unauthorized(c, "Unauthorized")
 	}
 
-	user, err := h.userService.GetUserByEmail(c.Context(), email)
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "User not found",
-		})
+		return response.BadRequest(c, err, "Invalid user ID")
 	}
-	return c.JSON(fiber.Map{
-		"data": user,
-	})
+
+	ctx := c.UserContext()
+
+	target, err := h.UserService.GetUser(ctx, uint(id))
+	if err != nil {
+		return userErrorResponse(c, err, "Failed to delete user")
+	}
+	if ok, err := h.policies.Authorize(c, service.ActionUserDelete, target); !ok {
+		return err
+	}
+
+	admin := c.Locals("user").(model.User)
+	if err := h.UserService.DeleteUser(ctx, &admin, uint(id)); err != nil {
+		return userErrorResponse(c, err, "Failed to delete user")
+	}
+
+	logger.Infof("User %d deleted by admin %v", id, adminID)
+
+	return response.Success(c, nil, "User deleted successfully")
 }
 
-// RegisterUserRoutes registers user routes using dependency injection
-func RegisterUserRoutes(app *fiber.App, db *gorm.DB) {
-	// Using instance-based handler with dependency injection
-	handler := NewUserHandler(db)
-	
-	userGroup := app.Group("/api/users")
-	userGroup.Get("/:id", handler.GetUser)
-	userGroup.Post("/", handler.CreateUser)
-	userGroup.Get("/", handler.GetUserByEmail)
+// userErrorResponse maps the errors of the user service to a response
+func userErrorResponse(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		return response.NotFound(c, "User not found")
+	case errors.Is(err, service.ErrCannotModifySelf),
+		errors.Is(err, service.ErrPrivilegeEscalation),
+		errors.Is(err, service.ErrProtectedUser):
+		return response.Forbidden(c, err.Error())
+	case errors.Is(err, service.ErrRoleNotFound),
+		errors.Is(err, service.ErrEmailTaken),
+		errors.Is(err, passwordhash.ErrPasswordTooLong):
+		return response.BadRequest(c, err, message)
+	default:
+		return response.InternalServerError(c, err, message)
+	}
 }
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-api/app"
+	"go-api/model"
+	"go-api/permission"
+	"go-api/policy"
+	"go-api/repository"
+	"go-api/shared/constant"
+
+	"gorm.io/gorm"
+)
+
+const ResourceUser = "user"
+
+// Actions on user accounts
+const (
+	ActionUserView        = "user.view"
+	ActionUserUpdate      = "user.update"
+	ActionUserDelete      = "user.delete"
+	ActionUserAssignRole  = "user.assign_role"
+	ActionUserImpersonate = "user.impersonate"
+)
+
+// RegisterPolicies registers the rules for user accounts. Users may view, update and delete
+// their own account, other accounts require the matching permission.
+func RegisterPolicies(p *app.Provider) {
+	userRepo := repository.NewUserRepository(p.DB)
+	p.Policies.RegisterResource(ResourceUser, func(ctx context.Context, id uint) (any, error) {
+		user, err := userRepo.FindByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, policy.ErrResourceNotFound
+			}
+			return nil, err
+		}
+		return user, nil
+	})
+
+	selfOr := func(code string) policy.Rule {
+		return policy.For(func(ctx context.Context, actor *model.User, user *model.User) (bool, error) {
+			if actor.ID == user.ID {
+				return true, nil
+			}
+			return p.Permissions.HasAll(ctx, actor, code)
+		})
+	}
+
+	p.Policies.Register(ResourceUser, ActionUserView, selfOr(permission.UsersRead))
+	p.Policies.Register(ResourceUser, ActionUserUpdate, selfOr(permission.UsersUpdate))
+	// Deleting someone else also requires holding every permission of their role
+	p.Policies.Register(ResourceUser, ActionUserDelete, policy.For(func(ctx context.Context, actor *model.User, user *model.User) (bool, error) {
+		if actor.ID == user.ID {
+			return true, nil
+		}
+		allowed, err := p.Permissions.HasAll(ctx, actor, permission.UsersDelete)
+		if err != nil || !allowed {
+			return false, err
+		}
+		return p.Permissions.CoversRole(ctx, actor, &user.Role)
+	}))
+	// Nobody changes their own role, so at least one admin always keeps managing roles, nor the
+	// role of users holding permissions the actor lacks
+	p.Policies.Register(ResourceUser, ActionUserAssignRole, policy.For(func(ctx context.Context, actor *model.User, user *model.User) (bool, error) {
+		if actor.ID == user.ID {
+			return false, nil
+		}
+		allowed, err := p.Permissions.HasAll(ctx, actor, permission.RolesAssign)
+		if err != nil || !allowed {
+			return false, err
+		}
+		return p.Permissions.CoversRole(ctx, actor, &user.Role)
+	}))
+	p.Policies.Register(ResourceUser, ActionUserImpersonate, policy.For(func(ctx context.Context, actor *model.User, user *model.User) (bool, error) {
+		if actor.ID == user.ID || user.Role.Code == constant.RoleCodeAdmin {
+			return false, nil
+		}
+		allowed, err := p.Permissions.HasAll(ctx, actor, permission.UsersImpersonate)
+		if err != nil || !allowed {
+			return false, err
+		}
+		return p.Permissions.CoversRole(ctx, actor, &user.Role)
+	}))
+}
@@ -2,53 +2,305 @@ package service
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"go-api/app"
+	"go-api/config"
+	"go-api/domain/user/entity"
 	"go-api/model"
+	"go-api/passwordhash"
 	"go-api/repository"
+	"go-api/shared/constant"
+	"go-api/shared/timezone"
+	"strings"
 
 	"gorm.io/gorm"
 )
 
-// UserService demonstrates how to create a service with proper dependency injection
+const defaultPerPage = 20
+
+var (
+	ErrUserNotFound        = errors.New("user not found")
+	ErrRoleNotFound        = errors.New("role not found")
+	ErrEmailTaken          = errors.New("email address is already in use")
+	ErrCannotModifySelf    = errors.New("admins cannot change their own role or delete their own account here")
+	ErrPrivilegeEscalation = errors.New("you cannot grant a role with permissions you do not hold")
+	ErrProtectedUser       = errors.New("you cannot modify a user with permissions you do not hold")
+)
+
+// UserService lets admins manage user accounts
 type UserService struct {
-	userRepo *repository.UserRepository
+	provider  *app.Provider
+	userRepo  *repository.UserRepository
+	roleRepo  *repository.RoleRepository
+	passwords *passwordhash.Hasher
 }
 
-// NewUserService creates a new user service with proper dependency injection
-func NewUserService(db *gorm.DB) *UserService {
+func NewUserService(p *app.Provider) *UserService {
 	return &UserService{
-		userRepo: repository.NewUserRepository(db),
+		provider:  p,
+		userRepo:  repository.NewUserRepository(p.DB),
+		roleRepo:  repository.NewRoleRepository(p.DB),
+		passwords: p.Passwords,
 	}
 }
 
-func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
-	if email == "" {
-		return nil, fmt.Errorf("email cannot be empty")
+// ListUsers returns a page of users matching the filters
+func (s *UserService) ListUsers(ctx context.Context, req *entity.ListUsersRequest) (*entity.UserListResponse, error) {
+	page, perPage := req.Page, req.PerPage
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = defaultPerPage
+	}
+
+	users, total, err := s.userRepo.List(ctx, strings.TrimSpace(req.Search), req.RoleID, (page-1)*perPage, perPage)
+	if err != nil {
+		return nil, err
 	}
-	
-	return s.userRepo.FindByEmail(ctx, email)
+
+	return &entity.UserListResponse{
+		Users:   users,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	}, nil
 }
 
-func (s *UserService) GetUserByID(ctx context.Context, id uint) (*model.User, error) {
-	if id == 0 {
-		return nil, fmt.Errorf("user ID cannot be empty")
+func (s *UserService) GetUser(ctx context.Context, id uint) (*model.User, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// CreateUser creates an account with a hashed password. The password must already satisfy the password policy.
+func (s *UserService) CreateUser(ctx context.Context, actor *model.User, req *entity.CreateUserRequest) (*model.User, error) {
+	email := strings.TrimSpace(req.Email)
+
+	exists, err := s.userRepo.EmailExists(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrEmailTaken
+	}
+
+	role, err := s.findRole(ctx, req.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureCoversRole(ctx, actor, role); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := s.passwords.Hash(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &model.User{
+		Name:     strings.TrimSpace(req.Name),
+		Email:    email,
+		RoleID:   role.ID,
+		Password: hashedPassword,
+	}
+	if req.EmailVerified {
+		verifiedAt := timezone.Now()
+		user.EmailVerifiedAt = &verifiedAt
+	}
+
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewUserRepository(tx).Create(ctx, user); err != nil {
+			return err
+		}
+		return recordPasswordHistory(ctx, tx, user.ID, hashedPassword)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetUser(ctx, user.ID)
+}
+
+// UpdateUser applies the given changes to a user. A new email address is unverified and a new
+// password or role signs the user out everywhere.
+func (s *UserService) UpdateUser(ctx context.Context, actor *model.User, id uint, req *entity.UpdateUserRequest) (*model.User, error) {
+	user, err := s.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// New credentials would let the actor sign in as the user and use their permissions
+	if (req.Email != nil || req.Password != nil) && id != actor.ID {
+		covered, err := s.provider.Permissions.CoversRole(ctx, actor, &user.Role)
+		if err != nil {
+			return nil, err
+		}
+		if !covered {
+			return nil, ErrProtectedUser
+		}
+	}
+
+	updates := map[string]interface{}{}
+	revokeSessions := false
+
+	if req.Name != nil {
+		updates["name"] = strings.TrimSpace(*req.Name)
 	}
-	
-	return s.userRepo.FindByID(ctx, id)
+
+	if req.Email != nil && !strings.EqualFold(strings.TrimSpace(*req.Email), user.Email) {
+		email := strings.TrimSpace(*req.Email)
+		exists, err := s.userRepo.EmailExists(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, ErrEmailTaken
+		}
+		updates["email"] = email
+		updates["email_verified_at"] = nil
+	}
+
+	if req.RoleID != nil && *req.RoleID != user.RoleID {
+		// Keeps at least the acting admin able to manage users
+		if id == actor.ID {
+			return nil, ErrCannotModifySelf
+		}
+		role, err := s.findRole(ctx, *req.RoleID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.ensureCoversRole(ctx, actor, role); err != nil {
+			return nil, err
+		}
+		updates["role_id"] = role.ID
+		revokeSessions = true
+	}
+
+	if req.Password != nil {
+		hashedPassword, err := s.passwords.Hash(*req.Password)
+		if err != nil {
+			return nil, err
+		}
+		updates["password"] = hashedPassword
+		revokeSessions = true
+	}
+
+	if len(updates) == 0 {
+		return user, nil
+	}
+
+	err = s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewUserRepository(tx).Update(ctx, id, updates); err != nil {
+			return err
+		}
+		if hashedPassword, ok := updates["password"].(string); ok {
+			if err := recordPasswordHistory(ctx, tx, id, hashedPassword); err != nil {
+				return err
+			}
+		}
+		if !revokeSessions {
+			return nil
+		}
+		return s.revokeAllUserSessions(ctx, tx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetUser(ctx, id)
+}
+
+// DeleteUser soft deletes a user and signs them out everywhere. Users holding permissions
+// the actor lacks are protected.
+func (s *UserService) DeleteUser(ctx context.Context, actor *model.User, id uint) error {
+	if id == actor.ID {
+		return ErrCannotModifySelf
+	}
+
+	user, err := s.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	covered, err := s.provider.Permissions.CoversRole(ctx, actor, &user.Role)
+	if err != nil {
+		return err
+	}
+	if !covered {
+		return ErrProtectedUser
+	}
+
+	return s.provider.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewUserRepository(tx).Delete(ctx, id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		// API keys authenticate without a session, so they have to go as well
+		if err := repository.NewAPIKeyRepository(tx).DeleteAllForUser(ctx, id); err != nil {
+			return err
+		}
+		return s.revokeAllUserSessions(ctx, tx, id)
+	})
+}
+
+// recordPasswordHistory stores a password set by an admin in the history the reuse check of
+// the user's own password changes reads, dropping entries beyond the configured history size
+func recordPasswordHistory(ctx context.Context, tx *gorm.DB, userID uint, hashedPassword string) error {
+	historySize := config.Get().PasswordHistorySize
+	if historySize <= 0 {
+		return nil
+	}
+
+	historyRepo := repository.NewPasswordHistoryRepository(tx)
+	if err := historyRepo.Create(ctx, userID, hashedPassword); err != nil {
+		return err
+	}
+
+	return historyRepo.Prune(ctx, userID, historySize)
+}
+
+// ensureCoversRole fails unless the actor holds every permission of role
+func (s *UserService) ensureCoversRole(ctx context.Context, actor *model.User, role *model.Role) error {
+	covered, err := s.provider.Permissions.CoversRole(ctx, actor, role)
+	if err != nil {
+		return err
+	}
+	if !covered {
+		return ErrPrivilegeEscalation
+	}
+	return nil
 }
 
-func (s *UserService) CreateUser(ctx context.Context, user *model.User) error {
-	if user.Email == "" {
-		return fmt.Errorf("email is required")
+// findRole returns the role with the given ID, or the USER role when id is zero
+func (s *UserService) findRole(ctx context.Context, id uint) (*model.Role, error) {
+	var role *model.Role
+	var err error
+	if id == 0 {
+		role, err = s.roleRepo.FindByCode(ctx, constant.RoleCodeUser)
+	} else {
+		role, err = s.roleRepo.FindByID(ctx, id)
 	}
-	
-	// Check if user already exists
-	existingUser, err := s.userRepo.FindByEmail(ctx, user.Email)
-	if err == nil && existingUser != nil {
-		return fmt.Errorf("user with email %s already exists", user.Email)
+	if err != nil {
+		if errors.Is(err, repository.ErrRoleNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
 	}
-	
-	return s.userRepo.Create(ctx, user)
+	return role, nil
 }
 
+// revokeAllUserSessions revokes every access and refresh token of a user using db
+func (s *UserService) revokeAllUserSessions(ctx context.Context, db *gorm.DB, userID uint) error {
+	if err := repository.NewRefreshTokenRepository(db).RevokeAllUserTokens(ctx, userID); err != nil {
+		return err
+	}
 
+	return s.provider.Tokens.WithDB(db).RevokeAllUserTokens(ctx, userID)
+}
@@ -37,7 +37,7 @@ func (s *EmailService) loadTemplates() {
 	templateDir := "email/templates"
 
 	// Define available templates
-	templates := []string{"welcome", "password_reset", "email_verification"}
+	templates := []string{"welcome", "password_reset", "email_verification", "account_locked", "email_change_confirmation", "email_change_notice", "magic_link", "account_deletion_scheduled"}
 
 	for _, tmplName := range templates {
 		htmlPath := filepath.Join(templateDir, tmplName+".html")
@@ -148,3 +148,58 @@ func (s *EmailService) SendEmailVerificationEmail(userEmail, userName, verificat
 
 	return s.SendTemplateEmail(userEmail, "Please Verify Your Email", "email_verification", data)
 }
+
+// SendAccountLockedEmail notifies a user that their account was locked after repeated failed logins
+func (s *EmailService) SendAccountLockedEmail(userEmail, userName string, failedAttempts, lockMinutes int) error {
+	data := EmailData{
+		"UserName":       userName,
+		"FailedAttempts": failedAttempts,
+		"LockMinutes":    lockMinutes,
+	}
+
+	return s.SendTemplateEmail(userEmail, "Your Account Has Been Temporarily Locked", "account_locked", data)
+}
+
+// SendEmailChangeConfirmationEmail sends the confirmation code for an email change to the new address
+func (s *EmailService) SendEmailChangeConfirmationEmail(newEmail, userName, confirmationCode string, expirationMinutes int) error {
+	data := EmailData{
+		"UserName":         userName,
+		"ConfirmationCode": confirmationCode,
+		"ConfirmationURL":  "", // Add your confirmation URL here if needed
+		"ExpirationTime":   expirationMinutes,
+	}
+
+	return s.SendTemplateEmail(newEmail, "Confirm Your New Email Address", "email_change_confirmation", data)
+}
+
+// SendEmailChangeNoticeEmail warns the current address that an email change was requested
+func (s *EmailService) SendEmailChangeNoticeEmail(userEmail, userName, newEmail string) error {
+	data := EmailData{
+		"UserName": userName,
+		"NewEmail": newEmail,
+	}
+
+	return s.SendTemplateEmail(userEmail, "Email Address Change Requested", "email_change_notice", data)
+}
+
+// SendMagicLinkEmail sends a one-time passwordless sign-in code using the magic_link template
+func (s *EmailService) SendMagicLinkEmail(userEmail, userName, loginToken string, expirationMinutes int) error {
+	data := EmailData{
+		"UserName":       userName,
+		"LoginToken":     loginToken,
+		"LoginURL":       "", // Add your magic link URL here if needed
+		"ExpirationTime": expirationMinutes,
+	}
+
+	return s.SendTemplateEmail(userEmail, "Your Sign-In Link", "magic_link", data)
+}
+
+// SendAccountDeletionScheduledEmail confirms that an account will be purged at the end of the grace period
+func (s *EmailService) SendAccountDeletionScheduledEmail(userEmail, userName string, purgeAt time.Time) error {
+	data := EmailData{
+		"UserName":  userName,
+		"PurgeDate": purgeAt.Format("January 2, 2006"),
+	}
+
+	return s.SendTemplateEmail(userEmail, "Your Account Is Scheduled for Deletion", "account_deletion_scheduled", data)
+}
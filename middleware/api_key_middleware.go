@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"go-api/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireSession rejects requests authenticated with an API key or an OAuth access token.
+// Use it on account management routes so a leaked key or a third-party client cannot take
+// over the account.
+// Must be registered after AuthMiddleware.
+func RequireSession() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := c.Locals("api_key").(*model.APIKey); ok {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "This endpoint cannot be used with an API key",
+				"code":  "SESSION_REQUIRED",
+			})
+		}
+		if _, ok := c.Locals("oauth_token").(*model.OAuthToken); ok {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "This endpoint cannot be used with an OAuth token",
+				"code":  "SESSION_REQUIRED",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// RequireScope requires API key and OAuth token requests to carry the given scope.
+// Requests authenticated with a user session have full access and pass through.
+// Must be registered after AuthMiddleware.
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		apiKey, ok := c.Locals("api_key").(*model.APIKey)
+		if ok && !apiKey.HasScope(scope) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "API key is missing the required scope: " + scope,
+				"code":  "INSUFFICIENT_SCOPE",
+			})
+		}
+		oauthToken, ok := c.Locals("oauth_token").(*model.OAuthToken)
+		if ok && !oauthToken.HasScope(scope) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "OAuth token is missing the required scope: " + scope,
+				"code":  "INSUFFICIENT_SCOPE",
+			})
+		}
+
+		return c.Next()
+	}
+}
@@ -3,6 +3,8 @@ package middleware
 import (
 	"go-api/app"
 	authService "go-api/domain/auth/service"
+	oauthService "go-api/domain/oauth/service"
+	"go-api/model"
 	"go-api/shared/logger"
 	"strings"
 
@@ -11,9 +13,16 @@ import (
 
 func AuthMiddleware(app *app.Provider) fiber.Handler {
 	service := authService.NewAuthService(app)
+	oauth := oauthService.NewOAuthService(app)
 	
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
+
+		// Machine clients authenticate with a personal API key instead of a bearer token
+		if apiKey := c.Get("X-API-Key"); authHeader == "" && apiKey != "" {
+			return authenticateAPIKey(c, service, apiKey)
+		}
+
 		if authHeader == "" {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error": "Authorization header is required",
@@ -39,6 +48,11 @@ func AuthMiddleware(app *app.Provider) fiber.Handler {
 			})
 		}
 
+		// Tokens issued to OAuth clients carry their own prefix
+		if strings.HasPrefix(token, model.OAuthAccessTokenPrefix) {
+			return authenticateOAuthToken(c, oauth, token)
+		}
+
 		// Get context with timeout from middleware
 		ctx := c.UserContext()
 
@@ -61,11 +75,126 @@ func AuthMiddleware(app *app.Provider) fiber.Handler {
 			})
 		}
 
+		// Keep session details (device, IP, last use) up to date
+		if err := service.RecordTokenUsage(ctx, accessToken, c.IP(), c.Get("User-Agent")); err != nil {
+			logger.Warnf("Failed to record token usage for token %d: %v", accessToken.ID, err)
+		}
+
 		// Add user info to context for downstream handlers
 		c.Locals("user_id", accessToken.UserID)
 		c.Locals("user", accessToken.User)
 		c.Locals("access_token", accessToken)
 
+		// An admin acting as the user keeps their own identity next to the user's
+		if accessToken.IsImpersonation() {
+			c.Locals("impersonator_id", *accessToken.ImpersonatorID)
+		}
+
+		return c.Next()
+	}
+}
+
+// authenticateAPIKey validates an X-API-Key header and sets the key owner in the context
+func authenticateAPIKey(c *fiber.Ctx, service *authService.AuthService, key string) error {
+	ctx := c.UserContext()
+
+	apiKey, err := service.ValidateAPIKey(ctx, key)
+	if err != nil {
+		// Log the validation attempt for security monitoring
+		logger.Warnf("API key validation failed for IP %s: %v", c.IP(), err)
+
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid or expired API key",
+			"code":  "INVALID_API_KEY",
+		})
+	}
+	// Validate that the owner of the key still exists
+	if apiKey.User.ID == 0 {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "API key or user not found",
+			"code":  "API_KEY_USER_NOT_FOUND",
+		})
+	}
+
+	if err := service.RecordAPIKeyUsage(ctx, apiKey); err != nil {
+		logger.Warnf("Failed to record usage for API key %d: %v", apiKey.ID, err)
+	}
+
+	// Add user info to context for downstream handlers
+	c.Locals("user_id", apiKey.UserID)
+	c.Locals("user", apiKey.User)
+	c.Locals("api_key", apiKey)
+
+	return c.Next()
+}
+
+// authenticateOAuthToken validates an access token issued to an OAuth client and sets the
+// user who authorized the client in the context
+func authenticateOAuthToken(c *fiber.Ctx, service *oauthService.OAuthService, token string) error {
+	ctx := c.UserContext()
+
+	oauthToken, err := service.ValidateAccessToken(ctx, token)
+	if err != nil {
+		// Log the validation attempt for security monitoring
+		logger.Warnf("OAuth token validation failed for IP %s: %v", c.IP(), err)
+
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid or expired token",
+			"code":  "INVALID_TOKEN",
+		})
+	}
+
+	// Client credentials tokens act for the client only and cannot access user resources
+	if oauthToken.UserID == nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "This endpoint requires a token issued on behalf of a user",
+			"code":  "USER_TOKEN_REQUIRED",
+		})
+	}
+	// Validate that the user who authorized the client still exists
+	if oauthToken.User == nil || oauthToken.User.ID == 0 {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Token or user not found",
+			"code":  "TOKEN_USER_NOT_FOUND",
+		})
+	}
+
+	// Add user info to context for downstream handlers
+	c.Locals("user_id", oauthToken.User.ID)
+	c.Locals("user", *oauthToken.User)
+	c.Locals("oauth_token", oauthToken)
+
+	return c.Next()
+}
+
+// OAuthClientMiddleware authenticates an access token issued to an OAuth client, including
+// the tokens of the client credentials grant that have no user. Only the token is set in
+// the context, so it must not guard routes that act on a user.
+func OAuthClientMiddleware(app *app.Provider) fiber.Handler {
+	oauth := oauthService.NewOAuthService(app)
+
+	return func(c *fiber.Ctx) error {
+		authHeader := c.Get("Authorization")
+		if !strings.HasPrefix(authHeader, "Bearer "+model.OAuthAccessTokenPrefix) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "An OAuth access token is required",
+				"code":  "INVALID_TOKEN",
+			})
+		}
+
+		oauthToken, err := oauth.ValidateAccessToken(c.UserContext(), authHeader[7:])
+		if err != nil {
+			// Log the validation attempt for security monitoring
+			logger.Warnf("OAuth token validation failed for IP %s: %v", c.IP(), err)
+
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Invalid or expired token",
+				"code":  "INVALID_TOKEN",
+			})
+		}
+
+		c.Locals("oauth_token", oauthToken)
+
 		return c.Next()
 	}
 }
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"go-api/shared/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireNoImpersonation rejects requests made by an admin impersonating a user.
+// Use it on endpoints that change credentials or create new tokens, those must only be
+// used by the account owner.
+// Must be registered after AuthMiddleware.
+func RequireNoImpersonation() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if impersonatorID, ok := c.Locals("impersonator_id").(uint); ok {
+			logger.Warnf("Admin %d tried %s %s while impersonating user %v", impersonatorID, c.Method(), c.Path(), c.Locals("user_id"))
+
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "This action is not allowed while impersonating a user",
+				"code":  "IMPERSONATION_FORBIDDEN",
+			})
+		}
+
+		return c.Next()
+	}
+}
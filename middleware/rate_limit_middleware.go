@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"encoding/base64"
+	"fmt"
 	"go-api/config"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -60,3 +63,72 @@ func AuthRateLimitMiddleware() fiber.Handler {
 		},
 	})
 }
+
+// ReauthRateLimitMiddleware creates a rate limiter for authenticated endpoints that check the
+// current password again. It is keyed by user so a stolen access token cannot be used to guess
+// the password from many addresses.
+// Must be registered after AuthMiddleware.
+func ReauthRateLimitMiddleware() fiber.Handler {
+	cfg := config.Get()
+
+	if !cfg.RateLimitEnabled {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return limiter.New(limiter.Config{
+		Max:        5,                // 5 password checks
+		Expiration: 15 * time.Minute, // per 15 minutes
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return fmt.Sprintf("reauth:%v", c.Locals("user_id"))
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error":   "Too many password attempts",
+				"message": "You have exceeded the maximum number of password attempts. Please try again in 15 minutes.",
+			})
+		},
+	})
+}
+
+// OAuthClientRateLimitMiddleware creates a rate limiter for the endpoints OAuth clients call
+// with their credentials. Every client gets its own budget per IP, so a busy client can
+// refresh and introspect tokens without sharing the login limit, while guessing a
+// client secret from one address stays throttled.
+func OAuthClientRateLimitMiddleware() fiber.Handler {
+	cfg := config.Get()
+
+	if !cfg.RateLimitEnabled {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	return limiter.New(limiter.Config{
+		Max:        cfg.RateLimitMax,
+		Expiration: cfg.RateLimitWindow,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return "oauth:" + oauthClientID(c) + ":" + c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"error":   "Too many requests",
+				"message": "Rate limit exceeded. Please try again later.",
+			})
+		},
+	})
+}
+
+// oauthClientID returns the client ID a request claims, from the HTTP Basic authorization
+// header or the form parameters. The value is unverified and only used as a limiter key.
+func oauthClientID(c *fiber.Ctx) string {
+	authHeader := c.Get("Authorization")
+	if strings.HasPrefix(authHeader, "Basic ") {
+		if decoded, err := base64.StdEncoding.DecodeString(authHeader[6:]); err == nil {
+			clientID, _, _ := strings.Cut(string(decoded), ":")
+			return clientID
+		}
+	}
+	return c.FormValue("client_id")
+}
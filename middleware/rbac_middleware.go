@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"fmt"
+	"go-api/app"
+	"go-api/model"
+	"go-api/permission"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireRole only lets users holding one of the given role codes through.
+// Must be registered after AuthMiddleware.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := c.Locals("user").(model.User)
+		if !ok || user.ID == 0 {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Authentication is required",
+				"code":  "UNAUTHENTICATED",
+			})
+		}
+
+		if !slices.Contains(roles, user.Role.Code) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Your role does not allow this action",
+				"code":  "ROLE_REQUIRED",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// RequirePermission only lets users holding every one of the given permissions through.
+// Permission codes must be registered in the permission package, unknown codes panic at startup.
+// Must be registered after AuthMiddleware.
+func RequirePermission(app *app.Provider, permissions ...string) fiber.Handler {
+	permission.MustBeRegistered(permissions...)
+
+	return func(c *fiber.Ctx) error {
+		user, ok := c.Locals("user").(model.User)
+		if !ok || user.ID == 0 {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Authentication is required",
+				"code":  "UNAUTHENTICATED",
+			})
+		}
+
+		allowed, err := app.Permissions.HasAll(c.UserContext(), &user, permissions...)
+		if err != nil {
+			// Handled by the error middleware as an internal server error
+			return fmt.Errorf("failed to resolve permissions of user %d: %w", user.ID, err)
+		}
+
+		if !allowed {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "You do not have permission to perform this action",
+				"code":  "PERMISSION_DENIED",
+			})
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"go-api/app"
+	"go-api/model"
+	"go-api/repository"
+	"go-api/tenant"
+	"slices"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// OrganizationHeader selects the active organization when the route has no organization parameter
+const OrganizationHeader = "X-Organization-ID"
+
+// ResolveOrganization makes the organization of the request the active tenant. The organization
+// is taken from the organizationID route parameter or else the X-Organization-ID header, and the
+// user must be a member of it. Queries on tenant scoped models are then limited to it.
+// Must be registered after AuthMiddleware.
+func ResolveOrganization(app *app.Provider) fiber.Handler {
+	memberRepo := repository.NewOrganizationMemberRepository(app.DB)
+
+	return func(c *fiber.Ctx) error {
+		user, ok := c.Locals("user").(model.User)
+		if !ok || user.ID == 0 {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Authentication is required",
+				"code":  "UNAUTHENTICATED",
+			})
+		}
+
+		rawID := c.Params("organizationID")
+		if rawID == "" {
+			rawID = c.Get(OrganizationHeader)
+		}
+		if rawID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "An organization must be selected with the " + OrganizationHeader + " header",
+				"code":  "ORGANIZATION_REQUIRED",
+			})
+		}
+
+		organizationID, err := strconv.ParseUint(rawID, 10, 32)
+		if err != nil || organizationID == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid organization ID",
+				"code":  "INVALID_ORGANIZATION",
+			})
+		}
+
+		member, err := memberRepo.FindMembership(c.UserContext(), uint(organizationID), user.ID)
+		if err != nil {
+			// Unknown organizations look the same as ones the user is not a member of
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error": "You are not a member of this organization",
+					"code":  "ORGANIZATION_ACCESS_DENIED",
+				})
+			}
+			// Handled by the error middleware as an internal server error
+			return fmt.Errorf("failed to resolve membership of user %d: %w", user.ID, err)
+		}
+
+		c.Locals("organization_id", member.OrganizationID)
+		c.Locals("organization_member", member)
+		c.SetUserContext(tenant.WithOrganization(c.UserContext(), member.OrganizationID))
+
+		return c.Next()
+	}
+}
+
+// RequireOrganizationRole only lets members holding one of the given organization roles through.
+// Must be registered after ResolveOrganization.
+func RequireOrganizationRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		member, ok := c.Locals("organization_member").(*model.OrganizationMember)
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "An organization must be selected with the " + OrganizationHeader + " header",
+				"code":  "ORGANIZATION_REQUIRED",
+			})
+		}
+
+		if !slices.Contains(roles, member.Role) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Your organization role does not allow this action",
+				"code":  "ORGANIZATION_ROLE_REQUIRED",
+			})
+		}
+
+		return c.Next()
+	}
+}
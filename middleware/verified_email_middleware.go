@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"go-api/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireVerifiedEmail rejects requests from users who have not verified their email address.
+// It must be registered after AuthMiddleware, which puts the user into the request locals.
+func RequireVerifiedEmail() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := c.Locals("user").(model.User)
+		if !ok || user.ID == 0 {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Authentication is required",
+				"code":  "UNAUTHENTICATED",
+			})
+		}
+
+		if !user.IsEmailVerified() {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Email address must be verified to access this resource",
+				"code":  "EMAIL_NOT_VERIFIED",
+			})
+		}
+
+		return c.Next()
+	}
+}
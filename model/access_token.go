@@ -5,11 +5,23 @@ import (
 	"time"
 )
 
+// TokenPrefixLength is the number of leading characters of a token kept for display
+const TokenPrefixLength = 8
+
 type AccessToken struct {
 	BaseModelAttributes
-	Token     string    `gorm:"uniqueIndex;not null" json:"token"`
-	UserID    uint      `gorm:"not null" json:"user_id"`
-	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	Token       string     `gorm:"-" json:"-"` // Raw token, only known when issued or presented by the client
+	TokenHash   string     `gorm:"uniqueIndex;not null" json:"-"`
+	TokenPrefix string     `gorm:"not null" json:"token_prefix"`
+	UserID      uint       `gorm:"not null" json:"user_id"`
+	SessionID   string     `gorm:"index" json:"-"` // Refresh token family the token was issued for
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	UserAgent   string     `gorm:"not null;default:''" json:"user_agent"`
+	IPAddress   string     `gorm:"column:ip_address;not null;default:''" json:"ip_address"`
+	DeviceLabel string     `gorm:"not null;default:''" json:"device_label"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	// Admin acting as the user, set on tokens issued by impersonation
+	ImpersonatorID *uint `gorm:"index" json:"impersonator_id,omitempty"`
 
 	User User `gorm:"foreignKey:UserID" json:"user"`
 }
@@ -18,3 +30,23 @@ type AccessToken struct {
 func (at *AccessToken) IsValid() bool {
 	return at.DeletedAt.Time.IsZero() && timezone.Now().Before(at.ExpiresAt)
 }
+
+// IsIdle reports whether the token has not been used for longer than idleTimeout.
+// A token that was never used counts from its creation, a zero timeout disables the check.
+func (at *AccessToken) IsIdle(idleTimeout time.Duration) bool {
+	if idleTimeout <= 0 {
+		return false
+	}
+
+	lastActivity := at.CreatedAt
+	if at.LastUsedAt != nil && at.LastUsedAt.After(lastActivity) {
+		lastActivity = *at.LastUsedAt
+	}
+
+	return timezone.Now().After(lastActivity.Add(idleTimeout))
+}
+
+// IsImpersonation reports whether the token was issued to an admin acting as the user
+func (at *AccessToken) IsImpersonation() bool {
+	return at.ImpersonatorID != nil
+}
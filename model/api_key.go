@@ -0,0 +1,48 @@
+package model
+
+import (
+	"go-api/shared/constant"
+	"go-api/shared/timezone"
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix marks personal API keys so they are recognizable in logs and secret scanners
+const APIKeyPrefix = "gak_"
+
+type APIKey struct {
+	BaseModelAttributes
+	Name       string     `gorm:"not null" json:"name"`
+	KeyHash    string     `gorm:"uniqueIndex;not null" json:"-"`
+	KeyPrefix  string     `gorm:"not null" json:"key_prefix"`
+	Scopes     string     `gorm:"not null;default:''" json:"-"` // Space separated list of scopes
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// ScopeList returns the scopes granted to the key
+func (k *APIKey) ScopeList() []string {
+	return strings.Fields(k.Scopes)
+}
+
+// HasScope checks if the key was granted the given scope. Keys act as their owner, so the
+// profile and email scopes of OAuth clients are covered by read.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.ScopeList() {
+		if s == scope || (s == constant.ScopeRead && (scope == constant.ScopeProfile || scope == constant.ScopeEmail)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValid checks if the key is not revoked and not expired. Keys without expiry never expire.
+func (k *APIKey) IsValid() bool {
+	if !k.DeletedAt.Time.IsZero() {
+		return false
+	}
+	return k.ExpiresAt == nil || timezone.Now().Before(*k.ExpiresAt)
+}
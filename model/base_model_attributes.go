@@ -25,3 +25,12 @@ func (b *BaseModelAttributes) BeforeUpdate(tx *gorm.DB) error {
 	b.UpdatedAt = time.Now()
 	return nil
 }
+
+// TenantAttributes marks a model as owned by an organization. Statements on such models are
+// scoped to the active organization of their context by the tenant plugin.
+type TenantAttributes struct {
+	OrganizationID uint `gorm:"not null;index" json:"organization_id"`
+}
+
+// TenantScoped implements tenant.Scoped
+func (TenantAttributes) TenantScoped() {}
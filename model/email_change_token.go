@@ -0,0 +1,23 @@
+package model
+
+import (
+	"go-api/shared/timezone"
+	"time"
+)
+
+// EmailChangeToken holds a pending email address until the owner confirms it from the new mailbox
+type EmailChangeToken struct {
+	BaseModelAttributes
+	TokenHash string     `gorm:"uniqueIndex;not null" json:"-"`
+	UserID    uint       `gorm:"not null" json:"user_id"`
+	NewEmail  string     `gorm:"not null" json:"new_email"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"user"`
+}
+
+// IsValid checks if the token can still be used (not used, not expired and not deleted)
+func (t *EmailChangeToken) IsValid() bool {
+	return t.UsedAt == nil && t.DeletedAt.Time.IsZero() && timezone.Now().Before(t.ExpiresAt)
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"go-api/shared/timezone"
+	"time"
+)
+
+// MagicLinkToken is a one-time passwordless login token sent by email
+type MagicLinkToken struct {
+	BaseModelAttributes
+	TokenHash string     `gorm:"uniqueIndex;not null" json:"-"`
+	UserID    uint       `gorm:"not null" json:"user_id"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"user"`
+}
+
+// IsValid checks if the login token can still be used (not used, not expired and not deleted)
+func (t *MagicLinkToken) IsValid() bool {
+	return t.UsedAt == nil && t.DeletedAt.Time.IsZero() && timezone.Now().Before(t.ExpiresAt)
+}
@@ -0,0 +1,164 @@
+package model
+
+import (
+	"go-api/shared/timezone"
+	"strings"
+	"time"
+)
+
+const (
+	// OAuthAccessTokenPrefix marks access tokens issued to OAuth clients
+	OAuthAccessTokenPrefix = "goa_"
+	// OAuthRefreshTokenPrefix marks refresh tokens issued to OAuth clients
+	OAuthRefreshTokenPrefix = "gor_"
+
+	OAuthTokenTypeAccess  = "access_token"
+	OAuthTokenTypeRefresh = "refresh_token"
+
+	OAuthGrantAuthorizationCode = "authorization_code"
+	OAuthGrantRefreshToken      = "refresh_token"
+	OAuthGrantClientCredentials = "client_credentials"
+)
+
+// OAuthClient is an application registered to obtain delegated access.
+// Public clients (SPAs, CLIs) have no secret and must use PKCE.
+type OAuthClient struct {
+	BaseModelAttributes
+	ClientID         string `gorm:"uniqueIndex;not null" json:"client_id"`
+	ClientSecretHash string `gorm:"not null;default:''" json:"-"`
+	Name             string `gorm:"not null" json:"name"`
+	RedirectURIs     string `gorm:"not null;default:''" json:"-"` // Space separated list of exact redirect URIs
+	GrantTypes       string `gorm:"not null" json:"-"`            // Space separated list of allowed grants
+	Scopes           string `gorm:"not null;default:''" json:"-"` // Space separated list of allowed scopes
+	Public           bool   `gorm:"not null;default:false" json:"public"`
+	CreatedByID      *uint  `json:"created_by_id"`
+}
+
+// TableName overrides GORM's default, which would split the OAuth initialism
+func (OAuthClient) TableName() string {
+	return "oauth_clients"
+}
+
+func (c *OAuthClient) RedirectURIList() []string {
+	return strings.Fields(c.RedirectURIs)
+}
+
+func (c *OAuthClient) GrantTypeList() []string {
+	return strings.Fields(c.GrantTypes)
+}
+
+func (c *OAuthClient) ScopeList() []string {
+	return strings.Fields(c.Scopes)
+}
+
+// AllowsRedirectURI checks the URI against the registered ones by exact match
+func (c *OAuthClient) AllowsRedirectURI(uri string) bool {
+	return containsField(c.RedirectURIs, uri)
+}
+
+func (c *OAuthClient) AllowsGrant(grantType string) bool {
+	return containsField(c.GrantTypes, grantType)
+}
+
+func (c *OAuthClient) AllowsScope(scope string) bool {
+	return containsField(c.Scopes, scope)
+}
+
+// OAuthAuthorizationCode is a single-use code exchanged by the client for tokens
+type OAuthAuthorizationCode struct {
+	BaseModelAttributes
+	CodeHash            string     `gorm:"uniqueIndex;not null" json:"-"`
+	ClientID            uint       `gorm:"not null" json:"client_id"`
+	UserID              uint       `gorm:"not null" json:"user_id"`
+	RedirectURI         string     `gorm:"not null" json:"redirect_uri"`
+	Scopes              string     `gorm:"not null;default:''" json:"scopes"`
+	CodeChallenge       string     `gorm:"not null;default:''" json:"-"`
+	CodeChallengeMethod string     `gorm:"not null;default:''" json:"-"`
+	GrantID             string     `gorm:"not null" json:"-"` // Shared with the tokens issued for this code
+	ExpiresAt           time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt              *time.Time `json:"used_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+func (OAuthAuthorizationCode) TableName() string {
+	return "oauth_authorization_codes"
+}
+
+// IsExpired checks if the code can no longer be exchanged because of its age
+func (c *OAuthAuthorizationCode) IsExpired() bool {
+	return !timezone.Now().Before(c.ExpiresAt)
+}
+
+// OAuthConsent records the scopes a user granted to a client
+type OAuthConsent struct {
+	BaseModelAttributes
+	UserID   uint   `gorm:"not null" json:"user_id"`
+	ClientID uint   `gorm:"not null" json:"client_id"`
+	Scopes   string `gorm:"not null;default:''" json:"-"`
+
+	Client OAuthClient `gorm:"foreignKey:ClientID" json:"client"`
+}
+
+func (OAuthConsent) TableName() string {
+	return "oauth_consents"
+}
+
+// Covers checks if every requested scope was already granted
+func (c *OAuthConsent) Covers(scopes []string) bool {
+	for _, scope := range scopes {
+		if !containsField(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *OAuthConsent) ScopeList() []string {
+	return strings.Fields(c.Scopes)
+}
+
+// OAuthToken is an access or refresh token issued to a client. Tokens of the
+// client credentials grant have no user.
+type OAuthToken struct {
+	BaseModelAttributes
+	TokenHash string     `gorm:"uniqueIndex;not null" json:"-"`
+	TokenType string     `gorm:"not null" json:"token_type"`
+	ClientID  uint       `gorm:"not null" json:"client_id"`
+	UserID    *uint      `json:"user_id"`
+	Scopes    string     `gorm:"not null;default:''" json:"scopes"`
+	GrantID   string     `gorm:"not null;index" json:"-"` // Tokens from the same authorization are revoked together
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"` // Set when a refresh token was rotated
+	RevokedAt *time.Time `json:"revoked_at"`
+
+	Client OAuthClient `gorm:"foreignKey:ClientID" json:"-"`
+	User   *User       `gorm:"foreignKey:UserID" json:"-"`
+}
+
+func (OAuthToken) TableName() string {
+	return "oauth_tokens"
+}
+
+// IsActive checks if the token is not revoked, not rotated and not expired
+func (t *OAuthToken) IsActive() bool {
+	return t.RevokedAt == nil && t.UsedAt == nil && t.DeletedAt.Time.IsZero() && timezone.Now().Before(t.ExpiresAt)
+}
+
+func (t *OAuthToken) ScopeList() []string {
+	return strings.Fields(t.Scopes)
+}
+
+func (t *OAuthToken) HasScope(scope string) bool {
+	return containsField(t.Scopes, scope)
+}
+
+// containsField checks if a space separated list contains value
+func containsField(list, value string) bool {
+	for _, field := range strings.Fields(list) {
+		if field == value {
+			return true
+		}
+	}
+	return false
+}
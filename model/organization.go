@@ -0,0 +1,28 @@
+package model
+
+import "go-api/shared/constant"
+
+// Organization groups users of one customer, data of an organization is only visible to its members
+type Organization struct {
+	BaseModelAttributes
+	Name string `gorm:"not null" json:"name"`
+	Slug string `gorm:"uniqueIndex;not null" json:"slug"`
+
+	Members []OrganizationMember `gorm:"foreignKey:OrganizationID" json:"members,omitempty"`
+}
+
+// OrganizationMember grants a user access to an organization with an organization role
+type OrganizationMember struct {
+	BaseModelAttributes
+	TenantAttributes
+	UserID uint   `gorm:"not null;index" json:"user_id"`
+	Role   string `gorm:"not null" json:"role"`
+
+	User         *User         `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Organization *Organization `gorm:"foreignKey:OrganizationID" json:"organization,omitempty"`
+}
+
+// CanManage reports whether the member may manage the organization and its members
+func (m *OrganizationMember) CanManage() bool {
+	return m.Role == constant.OrganizationRoleOwner || m.Role == constant.OrganizationRoleAdmin
+}
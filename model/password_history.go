@@ -0,0 +1,10 @@
+package model
+
+// PasswordHistory keeps the hash of a password a user had, so it cannot be reused
+type PasswordHistory struct {
+	BaseModelAttributes
+	UserID       uint   `gorm:"not null;index" json:"user_id"`
+	PasswordHash string `gorm:"not null" json:"-"`
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
@@ -0,0 +1,9 @@
+package model
+
+// Permission is a single action that can be granted to roles. The rows mirror the permission
+// registry in code and are synced on startup.
+type Permission struct {
+	BaseModelAttributes
+	Code        string `gorm:"uniqueIndex;not null" json:"code"`
+	Description string `gorm:"not null;default:''" json:"description"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"go-api/shared/timezone"
+	"time"
+)
+
+// RefreshToken is a single-use token belonging to a rotation family.
+// Every refresh consumes the current token and issues a new one in the same family,
+// so presenting an already used token means the family has been compromised.
+type RefreshToken struct {
+	BaseModelAttributes
+	TokenHash     string     `gorm:"uniqueIndex;not null" json:"-"`
+	FamilyID      string     `gorm:"index;not null" json:"family_id"`
+	ParentID      *uint      `json:"parent_id"`
+	UserID        uint       `gorm:"not null" json:"user_id"`
+	AccessTokenID *uint      `json:"access_token_id"`
+	ExpiresAt     time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt        *time.Time `json:"used_at"`
+	RevokedAt     *time.Time `json:"revoked_at"`
+	// Login time of the family, carried over on every rotation to cap the session age
+	SessionStartedAt time.Time `gorm:"not null" json:"session_started_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"user"`
+}
+
+// IsUsed reports whether the token has already been exchanged
+func (rt *RefreshToken) IsUsed() bool {
+	return rt.UsedAt != nil
+}
+
+// IsValid checks if the token can be exchanged (not used, not revoked and not expired)
+func (rt *RefreshToken) IsValid() bool {
+	return rt.UsedAt == nil && rt.RevokedAt == nil && rt.DeletedAt.Time.IsZero() && timezone.Now().Before(rt.ExpiresAt)
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// RevokedToken is a denylist entry for stateless access tokens.
+// An entry revokes either a single token (JTI), a whole session (SessionID)
+// or every token of the user issued before RevokedAt (neither set).
+type RevokedToken struct {
+	BaseModelAttributes
+	JTI       *string   `gorm:"column:jti;uniqueIndex" json:"jti"`
+	SessionID *string   `gorm:"index" json:"session_id"`
+	UserID    uint      `gorm:"not null" json:"user_id"`
+	RevokedAt time.Time `gorm:"not null" json:"revoked_at"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+}
@@ -5,5 +5,6 @@ type Role struct {
 	Code string `gorm:"uniqueIndex;not null" json:"code"`
 	Name string `gorm:"not null" json:"name"`
 
-	Users []User `gorm:"foreignKey:RoleID" json:"users"`
+	Users       []User       `gorm:"foreignKey:RoleID" json:"users"`
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions,omitempty"`
 }
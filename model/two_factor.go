@@ -0,0 +1,46 @@
+package model
+
+import (
+	"go-api/shared/timezone"
+	"time"
+)
+
+// TwoFactorCredential holds the TOTP secret of a user.
+// Two-factor authentication is only enforced once ConfirmedAt is set.
+type TwoFactorCredential struct {
+	BaseModelAttributes
+	UserID       uint       `gorm:"uniqueIndex;not null" json:"user_id"`
+	Secret       string     `gorm:"not null" json:"-"`
+	ConfirmedAt  *time.Time `json:"confirmed_at"`
+	LastUsedStep int64      `gorm:"not null;default:0" json:"-"` // Rejects replay of an already used code
+}
+
+// IsEnabled reports whether enrollment has been confirmed
+func (c *TwoFactorCredential) IsEnabled() bool {
+	return c.ConfirmedAt != nil
+}
+
+// TwoFactorRecoveryCode is a hashed single-use code that replaces a TOTP code
+type TwoFactorRecoveryCode struct {
+	BaseModelAttributes
+	UserID   uint       `gorm:"index;not null" json:"user_id"`
+	CodeHash string     `gorm:"uniqueIndex;not null" json:"-"`
+	UsedAt   *time.Time `json:"used_at"`
+}
+
+// TwoFactorChallenge is the short-lived token returned by the password step of a two-step login
+type TwoFactorChallenge struct {
+	BaseModelAttributes
+	TokenHash string     `gorm:"uniqueIndex;not null" json:"-"`
+	UserID    uint       `gorm:"not null" json:"user_id"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	Attempts  int        `gorm:"not null;default:0" json:"attempts"`
+
+	User User `gorm:"foreignKey:UserID" json:"user"`
+}
+
+// IsValid checks if the challenge can still be completed
+func (c *TwoFactorChallenge) IsValid(maxAttempts int) bool {
+	return c.UsedAt == nil && c.Attempts < maxAttempts && c.DeletedAt.Time.IsZero() && timezone.Now().Before(c.ExpiresAt)
+}
@@ -1,11 +1,38 @@
 package model
 
+import (
+	"go-api/shared/timezone"
+	"time"
+)
+
 type User struct {
 	BaseModelAttributes
-	Email    string `gorm:"uniqueIndex;not null" json:"email"`
-	Name     string `gorm:"nullable" json:"name"`
-	Password string `gorm:"nullable" json:"-"`
-	RoleID   uint   `gorm:"not null" json:"role_id"`
+	Email           string     `gorm:"uniqueIndex;not null" json:"email"`
+	Name            string     `gorm:"nullable" json:"name"`
+	Password        string     `gorm:"nullable" json:"-"`
+	RoleID          uint       `gorm:"not null" json:"role_id"`
+	EmailVerifiedAt *time.Time `gorm:"nullable" json:"email_verified_at"`
+
+	FailedLoginAttempts int        `gorm:"not null;default:0" json:"-"`
+	LockedUntil         *time.Time `gorm:"nullable" json:"locked_until"`
+
+	// Set when the user deleted their account, the account is purged once this time has passed
+	DeletionScheduledAt *time.Time `gorm:"nullable" json:"deletion_scheduled_at"`
 
 	Role Role `gorm:"foreignKey:RoleID" json:"role"`
 }
+
+// IsEmailVerified reports whether the user has confirmed their email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsDeletionScheduled reports whether the user deleted their account and it awaits purging
+func (u *User) IsDeletionScheduled() bool {
+	return u.DeletionScheduledAt != nil
+}
+
+// IsLocked reports whether the account is temporarily locked after too many failed logins
+func (u *User) IsLocked() bool {
+	return u.LockedUntil != nil && timezone.Now().Before(*u.LockedUntil)
+}
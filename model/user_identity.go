@@ -0,0 +1,41 @@
+package model
+
+import (
+	"go-api/shared/timezone"
+	"time"
+)
+
+// UserIdentity links an account at an external OpenID Connect provider to a local user
+type UserIdentity struct {
+	BaseModelAttributes
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	Provider    string     `gorm:"not null" json:"provider"`
+	Subject     string     `gorm:"not null" json:"-"`
+	Email       string     `gorm:"not null;default:''" json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// OIDCLoginState keeps the state, nonce and PKCE verifier of a login in progress at a provider.
+// When UserID is set the flow links the identity to that user instead of signing in.
+type OIDCLoginState struct {
+	BaseModelAttributes
+	StateHash    string     `gorm:"uniqueIndex;not null" json:"-"`
+	Provider     string     `gorm:"not null" json:"provider"`
+	Nonce        string     `gorm:"not null" json:"-"`
+	CodeVerifier string     `gorm:"not null" json:"-"`
+	UserID       *uint      `json:"user_id"`
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt       *time.Time `json:"used_at"`
+}
+
+// TableName overrides GORM's default, which would split the OIDC initialism
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
+
+// IsValid checks if the state can still be used (not used, not expired and not deleted)
+func (s *OIDCLoginState) IsValid() bool {
+	return s.UsedAt == nil && s.DeletedAt.Time.IsZero() && timezone.Now().Before(s.ExpiresAt)
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"go-api/shared/timezone"
+	"strings"
+	"time"
+)
+
+const (
+	WebAuthnCeremonyRegistration = "registration"
+	WebAuthnCeremonyLogin        = "login"
+)
+
+// WebAuthnCredential is a passkey registered by a user. The private key never leaves the
+// authenticator, only its public key and signature counter are stored.
+type WebAuthnCredential struct {
+	BaseModelAttributes
+	UserID          uint       `gorm:"not null;index" json:"user_id"`
+	Name            string     `gorm:"not null" json:"name"`
+	CredentialID    []byte     `gorm:"uniqueIndex;not null" json:"-"`
+	PublicKey       []byte     `gorm:"not null" json:"-"`
+	AttestationType string     `gorm:"not null;default:''" json:"-"`
+	AAGUID          []byte     `gorm:"column:aaguid" json:"-"`
+	SignCount       uint32     `gorm:"not null;default:0" json:"-"`
+	Transports      string     `gorm:"not null;default:''" json:"-"` // Space separated list of transports
+	BackupEligible  bool       `gorm:"not null;default:false" json:"backup_eligible"`
+	BackupState     bool       `gorm:"not null;default:false" json:"backup_state"`
+	LastUsedAt      *time.Time `json:"last_used_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName overrides GORM's default, which would split the WebAuthn name
+func (WebAuthnCredential) TableName() string {
+	return "webauthn_credentials"
+}
+
+func (c *WebAuthnCredential) TransportList() []string {
+	return strings.Fields(c.Transports)
+}
+
+// WebAuthnChallenge keeps the session data of a registration or login ceremony in progress.
+// It is looked up by the challenge the authenticator signs. Login challenges have no user
+// because the authenticator picks the passkey.
+type WebAuthnChallenge struct {
+	BaseModelAttributes
+	Challenge   string     `gorm:"uniqueIndex;not null" json:"-"`
+	Ceremony    string     `gorm:"not null" json:"ceremony"`
+	UserID      *uint      `json:"user_id"`
+	SessionData string     `gorm:"type:text;not null" json:"-"` // JSON encoded webauthn.SessionData
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt      *time.Time `json:"used_at"`
+}
+
+func (WebAuthnChallenge) TableName() string {
+	return "webauthn_challenges"
+}
+
+// IsValid checks if the challenge can still be used (not used, not expired and not deleted)
+func (c *WebAuthnChallenge) IsValid() bool {
+	return c.UsedAt == nil && c.DeletedAt.Time.IsZero() && timezone.Now().Before(c.ExpiresAt)
+}
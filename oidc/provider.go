@@ -0,0 +1,204 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api/config"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// httpTimeout bounds every request to a provider (discovery, JWKS, token exchange)
+const httpTimeout = 10 * time.Second
+
+var (
+	ErrUnknownProvider = errors.New("unknown login provider")
+	ErrMissingIDToken  = errors.New("provider did not return an id_token")
+	ErrNonceMismatch   = errors.New("id_token nonce does not match the login request")
+)
+
+// Identity is the verified information about a user returned by a provider
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// Provider runs the authorization code flow with PKCE against a single OpenID Connect issuer.
+// Discovery happens on first use so an unreachable provider does not block startup.
+type Provider struct {
+	name       string
+	cfg        config.OIDCProviderConfig
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	oauth2   *oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+func NewProvider(name string, cfg config.OIDCProviderConfig) *Provider {
+	return &Provider{
+		name:       name,
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: httpTimeout},
+	}
+}
+
+// Name returns the key of the provider in the configuration
+func (p *Provider) Name() string {
+	return p.name
+}
+
+// DisplayName returns the human readable provider name, falling back to the key
+func (p *Provider) DisplayName() string {
+	if p.cfg.DisplayName != "" {
+		return p.cfg.DisplayName
+	}
+	return p.name
+}
+
+// discover loads the provider metadata and builds the OAuth2 config and ID token verifier
+func (p *Provider) discover(ctx context.Context) (*oauth2.Config, *gooidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.oauth2 != nil {
+		return p.oauth2, p.verifier, nil
+	}
+
+	provider, err := gooidc.NewProvider(gooidc.ClientContext(ctx, p.httpClient), p.cfg.Issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("oidc discovery for %s failed: %w", p.name, err)
+	}
+
+	scopes := p.cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{gooidc.ScopeOpenID, "email", "profile"}
+	}
+
+	p.oauth2 = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       scopes,
+	}
+	// The key set outlives the request, so it gets a background context
+	p.verifier = provider.VerifierContext(gooidc.ClientContext(context.Background(), p.httpClient), &gooidc.Config{
+		ClientID: p.cfg.ClientID,
+	})
+
+	return p.oauth2, p.verifier, nil
+}
+
+// AuthCodeURL returns the URL the user is sent to, bound to the state, nonce and PKCE verifier
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	oauth2Config, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return oauth2Config.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier)), nil
+}
+
+// Exchange redeems the authorization code and verifies the returned ID token
+// (signature against the provider JWKS, issuer, audience, expiry and nonce)
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	oauth2Config, verifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := oauth2Config.Exchange(gooidc.ClientContext(ctx, p.httpClient), code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("code exchange with %s failed: %w", p.name, err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, ErrMissingIDToken
+	}
+
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("id_token verification failed: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified any    `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, err
+	}
+
+	return &Identity{
+		Provider:      p.name,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: isTrue(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// isTrue accepts email_verified as a boolean or as the string "true" sent by some providers
+func isTrue(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
+
+// Registry holds the configured providers by name
+type Registry struct {
+	providers map[string]*Provider
+}
+
+func NewRegistry(cfg map[string]config.OIDCProviderConfig) *Registry {
+	registry := &Registry{providers: make(map[string]*Provider, len(cfg))}
+	for name, providerCfg := range cfg {
+		registry.providers[name] = NewProvider(name, providerCfg)
+	}
+	return registry
+}
+
+func (r *Registry) Get(name string) (*Provider, error) {
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return provider, nil
+}
+
+// List returns the configured providers sorted by name
+func (r *Registry) List() []*Provider {
+	providers := make([]*Provider, 0, len(r.providers))
+	for _, provider := range r.providers {
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].name < providers[j].name
+	})
+	return providers
+}
+
+// GenerateCodeVerifier returns a random PKCE code verifier
+func GenerateCodeVerifier() string {
+	return oauth2.GenerateVerifier()
+}
@@ -0,0 +1,327 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"go-api/config"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc/oidctest"
+)
+
+const (
+	testClientID     = "go-api"
+	testClientSecret = "client-secret"
+	testKeyID        = "test-key"
+	testCode         = "authorization-code"
+)
+
+// fakeIssuer is an OpenID Connect provider serving discovery and JWKS through oidctest
+// and a token endpoint that redeems a single authorization code
+type fakeIssuer struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu            sync.Mutex
+	codeChallenge string         // Recorded from the authorization URL
+	claims        map[string]any // Claims of the issued id_token, nil sends no id_token
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	issuer := &fakeIssuer{t: t, key: key}
+	discovery := &oidctest.Server{
+		PublicKeys: []oidctest.PublicKey{{PublicKey: key.Public(), KeyID: testKeyID, Algorithm: "RS256"}},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", issuer.serveToken)
+	mux.Handle("/", discovery)
+
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	discovery.SetIssuer(issuer.server.URL)
+
+	return issuer
+}
+
+func (f *fakeIssuer) provider() *Provider {
+	return NewProvider("test", config.OIDCProviderConfig{
+		Issuer:       f.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  "https://app.example.com/auth/oidc/test/callback",
+	})
+}
+
+// authorize runs the browser half of the flow: it builds the authorization URL and records the
+// PKCE challenge the provider would store with the code
+func (f *fakeIssuer) authorize(p *Provider, nonce, codeVerifier string) {
+	f.t.Helper()
+
+	authURL, err := p.AuthCodeURL(context.Background(), "state", nonce, codeVerifier)
+	if err != nil {
+		f.t.Fatalf("AuthCodeURL: %v", err)
+	}
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		f.t.Fatalf("parse authorization URL: %v", err)
+	}
+
+	f.mu.Lock()
+	f.codeChallenge = parsed.Query().Get("code_challenge")
+	f.mu.Unlock()
+}
+
+// issue sets the claims of the next id_token, on top of valid defaults
+func (f *fakeIssuer) issue(nonce string, extra map[string]any) {
+	now := time.Now()
+	claims := map[string]any{
+		"iss":   f.server.URL,
+		"aud":   testClientID,
+		"sub":   "subject-1",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": nonce,
+		"email": "jane@example.com",
+		"name":  "Jane Doe",
+	}
+	for key, value := range extra {
+		if value == nil {
+			delete(claims, key)
+			continue
+		}
+		claims[key] = value
+	}
+
+	f.mu.Lock()
+	f.claims = claims
+	f.mu.Unlock()
+}
+
+func (f *fakeIssuer) serveToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != testClientID || clientSecret != testClientSecret {
+		tokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	f.mu.Lock()
+	challenge, claims := f.codeChallenge, f.claims
+	f.mu.Unlock()
+
+	if r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("code") != testCode {
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if challenge == "" || base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+		tokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	body := map[string]any{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	}
+	if claims != nil {
+		payload, err := json.Marshal(claims)
+		if err != nil {
+			f.t.Errorf("marshal claims: %v", err)
+			return
+		}
+		body["id_token"] = oidctest.SignIDToken(f.key, testKeyID, "RS256", string(payload))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+func tokenError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+func TestAuthCodeURLUsesDiscoveredEndpoint(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	p := issuer.provider()
+	codeVerifier := GenerateCodeVerifier()
+
+	authURL, err := p.AuthCodeURL(context.Background(), "state-value", "nonce-value", codeVerifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+
+	if !strings.HasPrefix(authURL, issuer.server.URL+"/auth?") {
+		t.Fatalf("authorization URL %q does not use the discovered endpoint", authURL)
+	}
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse authorization URL: %v", err)
+	}
+	query := parsed.Query()
+
+	sum := sha256.Sum256([]byte(codeVerifier))
+	want := map[string]string{
+		"client_id":             testClientID,
+		"response_type":         "code",
+		"state":                 "state-value",
+		"nonce":                 "nonce-value",
+		"code_challenge_method": "S256",
+		"code_challenge":        base64.RawURLEncoding.EncodeToString(sum[:]),
+		"scope":                 "openid email profile",
+	}
+	for key, value := range want {
+		if got := query.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestDiscoveryFailure(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	p := NewProvider("broken", config.OIDCProviderConfig{Issuer: server.URL, ClientID: testClientID})
+	if _, err := p.AuthCodeURL(context.Background(), "state", "nonce", GenerateCodeVerifier()); err == nil {
+		t.Fatal("expected discovery against a provider without metadata to fail")
+	}
+}
+
+func TestExchange(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	p := issuer.provider()
+	codeVerifier := GenerateCodeVerifier()
+
+	issuer.authorize(p, "nonce-value", codeVerifier)
+	issuer.issue("nonce-value", map[string]any{"email_verified": true})
+
+	identity, err := p.Exchange(context.Background(), testCode, codeVerifier, "nonce-value")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	want := Identity{
+		Provider:      "test",
+		Subject:       "subject-1",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		Name:          "Jane Doe",
+	}
+	if *identity != want {
+		t.Fatalf("identity = %+v, want %+v", *identity, want)
+	}
+}
+
+func TestExchangeRejectsWrongCodeVerifier(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	p := issuer.provider()
+
+	issuer.authorize(p, "nonce-value", GenerateCodeVerifier())
+	issuer.issue("nonce-value", nil)
+
+	if _, err := p.Exchange(context.Background(), testCode, GenerateCodeVerifier(), "nonce-value"); err == nil {
+		t.Fatal("expected the exchange with another code verifier to fail")
+	}
+}
+
+func TestExchangeRejectsNonceMismatch(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	p := issuer.provider()
+	codeVerifier := GenerateCodeVerifier()
+
+	issuer.authorize(p, "nonce-value", codeVerifier)
+	issuer.issue("another-nonce", nil)
+
+	_, err := p.Exchange(context.Background(), testCode, codeVerifier, "nonce-value")
+	if !errors.Is(err, ErrNonceMismatch) {
+		t.Fatalf("err = %v, want %v", err, ErrNonceMismatch)
+	}
+}
+
+func TestExchangeRejectsForeignAudience(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	p := issuer.provider()
+	codeVerifier := GenerateCodeVerifier()
+
+	issuer.authorize(p, "nonce-value", codeVerifier)
+	issuer.issue("nonce-value", map[string]any{"aud": "another-client"})
+
+	if _, err := p.Exchange(context.Background(), testCode, codeVerifier, "nonce-value"); err == nil {
+		t.Fatal("expected an id_token for another client to be rejected")
+	}
+}
+
+func TestExchangeRequiresIDToken(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	p := issuer.provider()
+	codeVerifier := GenerateCodeVerifier()
+
+	issuer.authorize(p, "nonce-value", codeVerifier)
+
+	_, err := p.Exchange(context.Background(), testCode, codeVerifier, "nonce-value")
+	if !errors.Is(err, ErrMissingIDToken) {
+		t.Fatalf("err = %v, want %v", err, ErrMissingIDToken)
+	}
+}
+
+func TestExchangeEmailVerified(t *testing.T) {
+	tests := []struct {
+		name  string
+		claim any
+		want  bool
+	}{
+		{name: "boolean true", claim: true, want: true},
+		{name: "string true", claim: "true", want: true},
+		{name: "boolean false", claim: false, want: false},
+		{name: "string false", claim: "false", want: false},
+		{name: "missing", claim: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newFakeIssuer(t)
+			p := issuer.provider()
+			codeVerifier := GenerateCodeVerifier()
+
+			issuer.authorize(p, "nonce-value", codeVerifier)
+			issuer.issue("nonce-value", map[string]any{"email_verified": tt.claim})
+
+			identity, err := p.Exchange(context.Background(), testCode, codeVerifier, "nonce-value")
+			if err != nil {
+				t.Fatalf("Exchange: %v", err)
+			}
+			if identity.EmailVerified != tt.want {
+				t.Fatalf("EmailVerified = %v, want %v", identity.EmailVerified, tt.want)
+			}
+		})
+	}
+}
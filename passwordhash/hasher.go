@@ -0,0 +1,91 @@
+package passwordhash
+
+import (
+	"errors"
+	"fmt"
+	"go-api/config"
+)
+
+const (
+	// AlgorithmArgon2id hashes passwords with Argon2id, encoded as a PHC string
+	AlgorithmArgon2id = "argon2id"
+	// AlgorithmBcrypt hashes passwords with bcrypt. Only the first 72 bytes of a password are used.
+	AlgorithmBcrypt = "bcrypt"
+)
+
+var (
+	ErrMismatch         = errors.New("password does not match")
+	ErrUnknownFormat    = errors.New("unknown password hash format")
+	ErrPasswordTooLong  = errors.New("password is too long for the configured hash algorithm")
+	ErrInvalidParameter = errors.New("invalid password hash parameters")
+)
+
+// Algorithm hashes and verifies passwords in one encoding
+type Algorithm interface {
+	// Hash returns the encoded hash of the password, including salt and parameters
+	Hash(password string) (string, error)
+	// Verify checks the password against an encoded hash, returning ErrMismatch if it does not match
+	Verify(encoded, password string) error
+	// Identifies reports whether the encoded hash was produced by this algorithm
+	Identifies(encoded string) bool
+	// NeedsRehash reports whether the hash was produced with other parameters than the configured ones
+	NeedsRehash(encoded string) bool
+}
+
+// Hasher hashes new passwords with the configured algorithm and verifies hashes of every
+// supported algorithm, so stored hashes can be upgraded when users sign in.
+type Hasher struct {
+	current    Algorithm
+	algorithms []Algorithm
+}
+
+// NewHasher creates the hasher selected in the configuration
+func NewHasher(cfg *config.Config) (*Hasher, error) {
+	argon2id := NewArgon2id(cfg.Argon2Memory, cfg.Argon2Iterations, cfg.Argon2Parallelism, cfg.Argon2SaltLength, cfg.Argon2KeyLength)
+	bcrypt := NewBcrypt(cfg.BcryptCost)
+
+	hasher := &Hasher{algorithms: []Algorithm{argon2id, bcrypt}}
+	switch cfg.PasswordHashAlgorithm {
+	case AlgorithmArgon2id, "":
+		hasher.current = argon2id
+	case AlgorithmBcrypt:
+		hasher.current = bcrypt
+	default:
+		return nil, fmt.Errorf("unknown password hash algorithm: %s", cfg.PasswordHashAlgorithm)
+	}
+
+	return hasher, nil
+}
+
+// Hash hashes a password with the configured algorithm
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.current.Hash(password)
+}
+
+// Verify checks a password against a hash of any supported algorithm.
+// It returns ErrMismatch if the password is wrong and ErrUnknownFormat for empty or foreign hashes.
+func (h *Hasher) Verify(encoded, password string) error {
+	algorithm := h.algorithmOf(encoded)
+	if algorithm == nil {
+		return ErrUnknownFormat
+	}
+	return algorithm.Verify(encoded, password)
+}
+
+// NeedsRehash reports whether a hash should be replaced because it uses another algorithm
+// or outdated parameters
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	if !h.current.Identifies(encoded) {
+		return true
+	}
+	return h.current.NeedsRehash(encoded)
+}
+
+func (h *Hasher) algorithmOf(encoded string) Algorithm {
+	for _, algorithm := range h.algorithms {
+		if algorithm.Identifies(encoded) {
+			return algorithm
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package passwordhash
+
+import (
+	"errors"
+	"go-api/config"
+	"strings"
+	"testing"
+)
+
+// testConfig uses cheap parameters so the tests stay fast
+func testConfig(algorithm string) *config.Config {
+	return &config.Config{
+		PasswordHashAlgorithm: algorithm,
+		Argon2Memory:          1024,
+		Argon2Iterations:      1,
+		Argon2Parallelism:     1,
+		Argon2SaltLength:      16,
+		Argon2KeyLength:       32,
+		BcryptCost:            4,
+	}
+}
+
+func newTestHasher(t *testing.T, algorithm string) *Hasher {
+	t.Helper()
+	hasher, err := NewHasher(testConfig(algorithm))
+	if err != nil {
+		t.Fatalf("NewHasher(%q): %v", algorithm, err)
+	}
+	return hasher
+}
+
+func TestNewHasherRejectsUnknownAlgorithm(t *testing.T) {
+	if _, err := NewHasher(testConfig("md5")); err == nil {
+		t.Fatal("expected an unknown algorithm to be rejected")
+	}
+}
+
+func TestHashAndVerify(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+		prefix    string
+	}{
+		{name: "default", algorithm: "", prefix: "$argon2id$"},
+		{name: "argon2id", algorithm: AlgorithmArgon2id, prefix: "$argon2id$"},
+		{name: "bcrypt", algorithm: AlgorithmBcrypt, prefix: "$2a$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasher := newTestHasher(t, tt.algorithm)
+
+			encoded, err := hasher.Hash("correct horse battery staple")
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+			if !strings.HasPrefix(encoded, tt.prefix) {
+				t.Fatalf("hash %q does not start with %q", encoded, tt.prefix)
+			}
+
+			if err := hasher.Verify(encoded, "correct horse battery staple"); err != nil {
+				t.Errorf("Verify with the right password: %v", err)
+			}
+			if err := hasher.Verify(encoded, "wrong password"); !errors.Is(err, ErrMismatch) {
+				t.Errorf("Verify with a wrong password = %v, want %v", err, ErrMismatch)
+			}
+			if hasher.NeedsRehash(encoded) {
+				t.Error("a fresh hash should not need a rehash")
+			}
+		})
+	}
+}
+
+func TestHashUsesRandomSalt(t *testing.T) {
+	hasher := newTestHasher(t, AlgorithmArgon2id)
+
+	first, err := hasher.Hash("password")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	second, err := hasher.Hash("password")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if first == second {
+		t.Fatal("hashing the same password twice returned the same hash")
+	}
+}
+
+func TestVerifyAcrossAlgorithms(t *testing.T) {
+	bcryptHash, err := newTestHasher(t, AlgorithmBcrypt).Hash("password")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	// Hashes of the previous algorithm keep working and are flagged for an upgrade
+	hasher := newTestHasher(t, AlgorithmArgon2id)
+	if err := hasher.Verify(bcryptHash, "password"); err != nil {
+		t.Fatalf("Verify bcrypt hash with argon2id hasher: %v", err)
+	}
+	if !hasher.NeedsRehash(bcryptHash) {
+		t.Fatal("a hash of another algorithm should need a rehash")
+	}
+}
+
+func TestNeedsRehashOnChangedParameters(t *testing.T) {
+	encoded, err := newTestHasher(t, AlgorithmArgon2id).Hash("password")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	cfg := testConfig(AlgorithmArgon2id)
+	cfg.Argon2Iterations = 2
+	hasher, err := NewHasher(cfg)
+	if err != nil {
+		t.Fatalf("NewHasher: %v", err)
+	}
+	if !hasher.NeedsRehash(encoded) {
+		t.Fatal("a hash with outdated parameters should need a rehash")
+	}
+	if err := hasher.Verify(encoded, "password"); err != nil {
+		t.Fatalf("Verify with outdated parameters: %v", err)
+	}
+}
+
+func TestVerifyRejectsMalformedHashes(t *testing.T) {
+	hasher := newTestHasher(t, AlgorithmArgon2id)
+
+	tests := []struct {
+		name    string
+		encoded string
+		want    error
+	}{
+		{name: "empty", encoded: "", want: ErrUnknownFormat},
+		{name: "plain text", encoded: "password", want: ErrUnknownFormat},
+		{name: "missing parts", encoded: "$argon2id$v=19$m=1024,t=1,p=1$c2FsdA", want: ErrUnknownFormat},
+		{name: "wrong version", encoded: "$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$a2V5", want: ErrInvalidParameter},
+		{name: "zero memory", encoded: "$argon2id$v=19$m=0,t=1,p=1$c2FsdA$a2V5", want: ErrInvalidParameter},
+		{name: "bad salt", encoded: "$argon2id$v=19$m=1024,t=1,p=1$!!!$a2V5", want: ErrInvalidParameter},
+		{name: "empty key", encoded: "$argon2id$v=19$m=1024,t=1,p=1$c2FsdA$", want: ErrInvalidParameter},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := hasher.Verify(tt.encoded, "password"); !errors.Is(err, tt.want) {
+				t.Fatalf("Verify = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBcryptRejectsLongPasswords(t *testing.T) {
+	hasher := newTestHasher(t, AlgorithmBcrypt)
+
+	if _, err := hasher.Hash(strings.Repeat("a", 73)); !errors.Is(err, ErrPasswordTooLong) {
+		t.Fatalf("Hash = %v, want %v", err, ErrPasswordTooLong)
+	}
+}
@@ -0,0 +1,135 @@
+package permission
+
+import (
+	"context"
+	"go-api/model"
+	"go-api/repository"
+	"go-api/shared/constant"
+	"go-api/shared/timezone"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type cacheEntry struct {
+	codes     map[string]struct{}
+	expiresAt time.Time
+}
+
+// Checker resolves the effective permissions of users. Permissions are granted to roles, so
+// the permission set of each role is cached for ttl. The ADMIN role holds every permission.
+type Checker struct {
+	permissionRepo *repository.PermissionRepository
+	ttl            time.Duration
+
+	mu      sync.RWMutex
+	entries map[uint]cacheEntry
+}
+
+func NewChecker(db *gorm.DB, ttl time.Duration) *Checker {
+	return &Checker{
+		permissionRepo: repository.NewPermissionRepository(db),
+		ttl:            ttl,
+		entries:        make(map[uint]cacheEntry),
+	}
+}
+
+// Permissions returns the codes of every permission the user holds
+func (c *Checker) Permissions(ctx context.Context, user *model.User) ([]string, error) {
+	if user.Role.Code == constant.RoleCodeAdmin {
+		codes := make([]string, 0, len(registry))
+		for _, definition := range registry {
+			codes = append(codes, definition.Code)
+		}
+		return codes, nil
+	}
+
+	granted, err := c.rolePermissions(ctx, user.RoleID)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, len(granted))
+	for code := range granted {
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// HasAll reports whether the user holds every one of the given permissions
+func (c *Checker) HasAll(ctx context.Context, user *model.User, codes ...string) (bool, error) {
+	if user.Role.Code == constant.RoleCodeAdmin {
+		return true, nil
+	}
+
+	granted, err := c.rolePermissions(ctx, user.RoleID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, code := range codes {
+		if _, ok := granted[code]; !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// CoversRole reports whether the actor holds every permission granted to role, so granting the
+// role or acting on its holders cannot give the actor more than they already have. Only admins
+// cover the ADMIN role.
+func (c *Checker) CoversRole(ctx context.Context, actor *model.User, role *model.Role) (bool, error) {
+	if actor.Role.Code == constant.RoleCodeAdmin {
+		return true, nil
+	}
+	if role.Code == constant.RoleCodeAdmin {
+		return false, nil
+	}
+
+	granted, err := c.rolePermissions(ctx, role.ID)
+	if err != nil {
+		return false, err
+	}
+
+	codes := make([]string, 0, len(granted))
+	for code := range granted {
+		codes = append(codes, code)
+	}
+	return c.HasAll(ctx, actor, codes...)
+}
+
+// Invalidate drops the cached permissions of a role after its grants changed
+func (c *Checker) Invalidate(roleID uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, roleID)
+}
+
+// rolePermissions returns the permissions granted to a role, from the cache when possible
+func (c *Checker) rolePermissions(ctx context.Context, roleID uint) (map[string]struct{}, error) {
+	now := timezone.Now()
+
+	c.mu.RLock()
+	entry, ok := c.entries[roleID]
+	c.mu.RUnlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.codes, nil
+	}
+
+	codes, err := c.permissionRepo.FindCodesByRole(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	granted := make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		granted[code] = struct{}{}
+	}
+
+	c.mu.Lock()
+	c.entries[roleID] = cacheEntry{codes: granted, expiresAt: now.Add(c.ttl)}
+	c.mu.Unlock()
+
+	return granted, nil
+}
@@ -0,0 +1,161 @@
+package permission
+
+import (
+	"context"
+	"go-api/model"
+	"go-api/shared/constant"
+	"slices"
+	"testing"
+	"time"
+)
+
+const (
+	adminRoleID   uint = 1
+	managerRoleID uint = 2
+	supportRoleID uint = 3
+	viewerRoleID  uint = 4
+	emptyRoleID   uint = 5
+)
+
+// newTestChecker returns a checker whose cache already holds the grants of every test role, so
+// no database is needed as long as only those roles are resolved
+func newTestChecker() *Checker {
+	checker := NewChecker(nil, time.Hour)
+	grants := map[uint][]string{
+		managerRoleID: {UsersRead, UsersCreate, UsersUpdate, RolesRead, RolesAssign},
+		supportRoleID: {UsersRead, UsersUnlock},
+		viewerRoleID:  {UsersRead},
+		emptyRoleID:   {},
+	}
+	for roleID, codes := range grants {
+		granted := make(map[string]struct{}, len(codes))
+		for _, code := range codes {
+			granted[code] = struct{}{}
+		}
+		checker.entries[roleID] = cacheEntry{codes: granted, expiresAt: time.Now().Add(time.Hour)}
+	}
+	return checker
+}
+
+func testRole(id uint) *model.Role {
+	codes := map[uint]string{
+		adminRoleID:   constant.RoleCodeAdmin,
+		managerRoleID: "MANAGER",
+		supportRoleID: "SUPPORT",
+		viewerRoleID:  "VIEWER",
+		emptyRoleID:   constant.RoleCodeUser,
+	}
+	role := &model.Role{Code: codes[id]}
+	role.ID = id
+	return role
+}
+
+func testUser(roleID uint) *model.User {
+	return &model.User{RoleID: roleID, Role: *testRole(roleID)}
+}
+
+func TestPermissions(t *testing.T) {
+	checker := newTestChecker()
+
+	tests := []struct {
+		name   string
+		roleID uint
+		want   []string
+	}{
+		{name: "admin holds every permission", roleID: adminRoleID, want: []string{
+			UsersRead, UsersCreate, UsersUpdate, UsersDelete, UsersUnlock, UsersImpersonate,
+			RolesRead, RolesManage, RolesAssign, OAuthClientsManage,
+		}},
+		{name: "granted permissions", roleID: supportRoleID, want: []string{UsersRead, UsersUnlock}},
+		{name: "no permissions", roleID: emptyRoleID, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checker.Permissions(context.Background(), testUser(tt.roleID))
+			if err != nil {
+				t.Fatalf("Permissions: %v", err)
+			}
+			slices.Sort(got)
+			want := slices.Clone(tt.want)
+			slices.Sort(want)
+			if !slices.Equal(got, want) {
+				t.Fatalf("Permissions = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestHasAll(t *testing.T) {
+	checker := newTestChecker()
+
+	tests := []struct {
+		name   string
+		roleID uint
+		codes  []string
+		want   bool
+	}{
+		{name: "admin", roleID: adminRoleID, codes: []string{UsersDelete, OAuthClientsManage}, want: true},
+		{name: "all granted", roleID: managerRoleID, codes: []string{UsersRead, RolesAssign}, want: true},
+		{name: "one missing", roleID: managerRoleID, codes: []string{UsersRead, UsersDelete}, want: false},
+		{name: "none requested", roleID: emptyRoleID, codes: nil, want: true},
+		{name: "nothing granted", roleID: emptyRoleID, codes: []string{UsersRead}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checker.HasAll(context.Background(), testUser(tt.roleID), tt.codes...)
+			if err != nil {
+				t.Fatalf("HasAll: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("HasAll(%q) = %v, want %v", tt.codes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoversRole(t *testing.T) {
+	checker := newTestChecker()
+
+	tests := []struct {
+		name   string
+		actor  uint
+		target uint
+		want   bool
+	}{
+		{name: "admin covers admin", actor: adminRoleID, target: adminRoleID, want: true},
+		{name: "admin covers any role", actor: adminRoleID, target: managerRoleID, want: true},
+		{name: "only admins cover admin", actor: managerRoleID, target: adminRoleID, want: false},
+		{name: "own role", actor: managerRoleID, target: managerRoleID, want: true},
+		{name: "subset of permissions", actor: managerRoleID, target: viewerRoleID, want: true},
+		{name: "role without permissions", actor: viewerRoleID, target: emptyRoleID, want: true},
+		{name: "superset of permissions", actor: viewerRoleID, target: managerRoleID, want: false},
+		{name: "overlapping permissions", actor: managerRoleID, target: supportRoleID, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checker.CoversRole(context.Background(), testUser(tt.actor), testRole(tt.target))
+			if err != nil {
+				t.Fatalf("CoversRole: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("CoversRole = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	checker := newTestChecker()
+
+	checker.Invalidate(viewerRoleID)
+
+	checker.mu.RLock()
+	_, cached := checker.entries[viewerRoleID]
+	checker.mu.RUnlock()
+	if cached {
+		t.Fatal("Invalidate should drop the cached permissions of the role")
+	}
+}
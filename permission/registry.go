@@ -0,0 +1,86 @@
+// Package permission holds the registry of permissions known to the application and resolves
+// the permissions granted to users through their role.
+package permission
+
+import (
+	"context"
+	"fmt"
+	"go-api/model"
+	"go-api/repository"
+
+	"gorm.io/gorm"
+)
+
+// Permission codes, grouped by the resource they apply to
+const (
+	UsersRead        = "users.read"
+	UsersCreate      = "users.create"
+	UsersUpdate      = "users.update"
+	UsersDelete      = "users.delete"
+	UsersUnlock      = "users.unlock"
+	UsersImpersonate = "users.impersonate"
+
+	RolesRead   = "roles.read"
+	RolesManage = "roles.manage"
+	RolesAssign = "roles.assign"
+
+	OAuthClientsManage = "oauth_clients.manage"
+)
+
+// Definition describes a registered permission
+type Definition struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+// registry lists every permission the application checks. New permissions must be added here
+// so they are stored in the database and can be granted to roles.
+var registry = []Definition{
+	{Code: UsersRead, Description: "List and view user accounts"},
+	{Code: UsersCreate, Description: "Create user accounts"},
+	{Code: UsersUpdate, Description: "Update user accounts, including their password and role"},
+	{Code: UsersDelete, Description: "Delete user accounts"},
+	{Code: UsersUnlock, Description: "Lift the lockout of user accounts"},
+	{Code: UsersImpersonate, Description: "Sign in as another user"},
+	{Code: RolesRead, Description: "List roles and the permissions granted to them"},
+	{Code: RolesManage, Description: "Create, update and delete roles and grant permissions to them"},
+	{Code: RolesAssign, Description: "Change the role of users"},
+	{Code: OAuthClientsManage, Description: "Register and remove OAuth clients"},
+}
+
+// All returns every registered permission
+func All() []Definition {
+	return append([]Definition(nil), registry...)
+}
+
+// IsRegistered reports whether code is a registered permission
+func IsRegistered(code string) bool {
+	for _, definition := range registry {
+		if definition.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// MustBeRegistered panics on unknown codes, a typo in a route definition must not silently deny access
+func MustBeRegistered(codes ...string) {
+	for _, code := range codes {
+		if !IsRegistered(code) {
+			panic(fmt.Sprintf("permission %q is not registered", code))
+		}
+	}
+}
+
+// Sync stores the registered permissions in the database. Permissions removed from the
+// registry are kept, so grants survive until they are cleaned up by hand.
+func Sync(ctx context.Context, db *gorm.DB) error {
+	permissions := make([]model.Permission, 0, len(registry))
+	for _, definition := range registry {
+		permissions = append(permissions, model.Permission{
+			Code:        definition.Code,
+			Description: definition.Description,
+		})
+	}
+	return repository.NewPermissionRepository(db).Upsert(ctx, permissions)
+}
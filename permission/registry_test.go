@@ -0,0 +1,57 @@
+package permission
+
+import "testing"
+
+func TestIsRegistered(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{code: UsersRead, want: true},
+		{code: OAuthClientsManage, want: true},
+		{code: "users.reads", want: false},
+		{code: "USERS.READ", want: false},
+		{code: "", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRegistered(tt.code); got != tt.want {
+			t.Errorf("IsRegistered(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestMustBeRegistered(t *testing.T) {
+	tests := []struct {
+		name      string
+		codes     []string
+		wantPanic bool
+	}{
+		{name: "registered", codes: []string{UsersRead, RolesManage}, wantPanic: false},
+		{name: "none", codes: nil, wantPanic: false},
+		{name: "unknown", codes: []string{UsersRead, "users.raed"}, wantPanic: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recovered := recover(); (recovered != nil) != tt.wantPanic {
+					t.Fatalf("panic = %v, want panic %v", recovered, tt.wantPanic)
+				}
+			}()
+			MustBeRegistered(tt.codes...)
+		})
+	}
+}
+
+func TestAllReturnsCopy(t *testing.T) {
+	all := All()
+	if len(all) != len(registry) {
+		t.Fatalf("All returned %d permissions, want %d", len(all), len(registry))
+	}
+
+	all[0].Code = "changed"
+	if registry[0].Code == "changed" {
+		t.Fatal("modifying the result of All should not change the registry")
+	}
+}
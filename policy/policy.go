@@ -0,0 +1,135 @@
+// Package policy decides whether a user may perform an action on a specific resource.
+// Domains register a rule per action, rules can combine permissions with properties of the
+// resource, e.g. users may update their own account while admins may update anyone.
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api/model"
+	"go-api/shared/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	ErrUnknownResource  = errors.New("unknown resource")
+	ErrResourceNotFound = errors.New("resource not found")
+)
+
+// Rule reports whether actor may perform an action on resource
+type Rule func(ctx context.Context, actor *model.User, resource any) (bool, error)
+
+// Loader loads a resource by ID, it returns ErrResourceNotFound when the resource does not exist
+type Loader func(ctx context.Context, id uint) (any, error)
+
+// For adapts a rule written for a concrete resource type, any other resource is denied
+func For[T any](rule func(ctx context.Context, actor *model.User, resource T) (bool, error)) Rule {
+	return func(ctx context.Context, actor *model.User, resource any) (bool, error) {
+		typed, ok := resource.(T)
+		if !ok {
+			return false, nil
+		}
+		return rule(ctx, actor, typed)
+	}
+}
+
+type resourcePolicy struct {
+	load    Loader
+	actions []string
+}
+
+// Registry holds the rules of every action. Resources and rules are registered on startup,
+// before requests are served, registering is not safe for concurrent use.
+type Registry struct {
+	resources map[string]*resourcePolicy
+	rules     map[string]Rule
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		resources: make(map[string]*resourcePolicy),
+		rules:     make(map[string]Rule),
+	}
+}
+
+// RegisterResource registers a resource type and how to load it by ID
+func (r *Registry) RegisterResource(resource string, load Loader) {
+	if _, exists := r.resources[resource]; exists {
+		panic(fmt.Sprintf("policy resource %q is already registered", resource))
+	}
+	r.resources[resource] = &resourcePolicy{load: load}
+}
+
+// Register registers the rule of an action on a resource. Action names are global, by
+// convention they are prefixed with the resource, e.g. "user.update".
+func (r *Registry) Register(resource, action string, rule Rule) {
+	policy, exists := r.resources[resource]
+	if !exists {
+		panic(fmt.Sprintf("policy resource %q is not registered", resource))
+	}
+	if _, exists := r.rules[action]; exists {
+		panic(fmt.Sprintf("policy action %q is already registered", action))
+	}
+	policy.actions = append(policy.actions, action)
+	r.rules[action] = rule
+}
+
+// Can reports whether actor may perform action on resource. Unknown actions are denied.
+func (r *Registry) Can(ctx context.Context, actor *model.User, action string, resource any) (bool, error) {
+	rule, exists := r.rules[action]
+	if !exists || actor == nil {
+		return false, nil
+	}
+	return rule(ctx, actor, resource)
+}
+
+// AllowedActions loads a resource and returns the actions actor may perform on it
+func (r *Registry) AllowedActions(ctx context.Context, actor *model.User, resource string, id uint) ([]string, error) {
+	policy, exists := r.resources[resource]
+	if !exists {
+		return nil, ErrUnknownResource
+	}
+
+	loaded, err := policy.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make([]string, 0, len(policy.actions))
+	for _, action := range policy.actions {
+		ok, err := r.Can(ctx, actor, action, loaded)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			allowed = append(allowed, action)
+		}
+	}
+	return allowed, nil
+}
+
+// Authorize checks whether the authenticated user may perform action on resource. When they
+// may not, the response is already written and handlers return the error as is:
+//
+//	if ok, err := h.policies.Authorize(c, service.ActionUserUpdate, user); !ok {
+//		return err
+//	}
+//
+// Must be used behind AuthMiddleware.
+func (r *Registry) Authorize(c *fiber.Ctx, action string, resource any) (bool, error) {
+	actor, ok := c.Locals("user").(model.User)
+	if !ok || actor.ID == 0 {
+		return false, response.Unauthorized(c, "Unauthorized")
+	}
+
+	allowed, err := r.Can(c.UserContext(), &actor, action, resource)
+	if err != nil {
+		return false, response.InternalServerError(c, err, "Failed to check authorization")
+	}
+	if !allowed {
+		return false, response.Forbidden(c, "You are not allowed to perform this action")
+	}
+	return true, nil
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"go-api/model"
+	"go-api/shared/securetoken"
 	"go-api/shared/timezone"
 
 	"gorm.io/gorm"
@@ -32,26 +33,51 @@ func (r *AccessTokenRepository) generateSecureToken() (string, error) {
 }
 
 func (r *AccessTokenRepository) Create(ctx context.Context, userID uint, expiresIn time.Duration) (*model.AccessToken, error) {
+	return r.CreateForSession(ctx, userID, "", expiresIn)
+}
+
+// CreateForSession creates an access token linked to a refresh token family
+func (r *AccessTokenRepository) CreateForSession(ctx context.Context, userID uint, sessionID string, expiresIn time.Duration) (*model.AccessToken, error) {
+	return r.create(ctx, &model.AccessToken{
+		UserID:    userID,
+		SessionID: sessionID,
+		ExpiresAt: timezone.Now().Add(expiresIn), // Use timezone-aware time
+	})
+}
+
+// CreateImpersonation creates a token that lets an admin act as the user. It is not linked to
+// a session, so it cannot be refreshed.
+func (r *AccessTokenRepository) CreateImpersonation(ctx context.Context, userID, impersonatorID uint, expiresIn time.Duration) (*model.AccessToken, error) {
+	return r.create(ctx, &model.AccessToken{
+		UserID:         userID,
+		ImpersonatorID: &impersonatorID,
+		ExpiresAt:      timezone.Now().Add(expiresIn),
+	})
+}
+
+// create generates the raw token for accessToken and stores it
+func (r *AccessTokenRepository) create(ctx context.Context, accessToken *model.AccessToken) (*model.AccessToken, error) {
 	token, err := r.generateSecureToken()
 	if err != nil {
 		return nil, err
 	}
 
-	accessToken := &model.AccessToken{
-		Token:     token,
-		UserID:    userID,
-		ExpiresAt: timezone.Now().Add(expiresIn), // Use timezone-aware time
-	}
+	// Only the digest is stored, a database dump must not leak live sessions
+	accessToken.TokenHash = securetoken.Hash(token)
+	accessToken.TokenPrefix = token[:model.TokenPrefixLength]
 
 	if err := r.db.WithContext(ctx).Create(accessToken).Error; err != nil {
 		return nil, err
 	}
 
 	// Load the user relationship
-	if err := r.db.WithContext(ctx).Preload("User").First(accessToken, accessToken.ID).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("User.Role").First(accessToken, accessToken.ID).Error; err != nil {
 		return nil, err
 	}
 
+	// The raw token is returned to the caller once and never persisted
+	accessToken.Token = token
+
 	return accessToken, nil
 }
 
@@ -59,17 +85,35 @@ func (r *AccessTokenRepository) FindByToken(ctx context.Context, token string) (
 	var accessToken model.AccessToken
 
 	// Only find tokens that are not deleted
-	err := r.db.WithContext(ctx).Preload("User").Where("token = ?", token).First(&accessToken).Error
+	err := r.db.WithContext(ctx).Preload("User.Role").Where("token_hash = ?", securetoken.Hash(token)).First(&accessToken).Error
 	if err != nil {
 		return nil, err
 	}
 
+	accessToken.Token = token
+
 	return &accessToken, nil
 }
 
 func (r *AccessTokenRepository) RevokeToken(ctx context.Context, token string) error {
 	// Soft delete the token by setting deleted_at
-	return r.db.WithContext(ctx).Where("token = ?", token).Delete(&model.AccessToken{}).Error
+	return r.db.WithContext(ctx).Where("token_hash = ?", securetoken.Hash(token)).Delete(&model.AccessToken{}).Error
+}
+
+// RevokeByIDs soft deletes the given access tokens
+func (r *AccessTokenRepository) RevokeByIDs(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&model.AccessToken{}).Error
+}
+
+// RevokeSession soft deletes all tokens issued for a session
+func (r *AccessTokenRepository) RevokeSession(ctx context.Context, sessionID string) error {
+	if sessionID == "" {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("session_id = ?", sessionID).Delete(&model.AccessToken{}).Error
 }
 
 func (r *AccessTokenRepository) RevokeAllUserTokens(ctx context.Context, userID uint) error {
@@ -77,6 +121,47 @@ func (r *AccessTokenRepository) RevokeAllUserTokens(ctx context.Context, userID
 	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.AccessToken{}).Error
 }
 
+// FindActiveByUser lists the unexpired, unrevoked tokens of a user, most recently used first
+func (r *AccessTokenRepository) FindActiveByUser(ctx context.Context, userID uint) ([]model.AccessToken, error) {
+	var accessTokens []model.AccessToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND expires_at > ?", userID, timezone.Now()).
+		Order("last_used_at DESC NULLS LAST").
+		Order("created_at DESC").
+		Find(&accessTokens).Error
+	return accessTokens, err
+}
+
+// FindByID retrieves an unrevoked token by ID
+func (r *AccessTokenRepository) FindByID(ctx context.Context, id uint) (*model.AccessToken, error) {
+	var accessToken model.AccessToken
+	err := r.db.WithContext(ctx).First(&accessToken, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &accessToken, nil
+}
+
+// FindByIDForUser retrieves a token by ID only if it belongs to the given user
+func (r *AccessTokenRepository) FindByIDForUser(ctx context.Context, id, userID uint) (*model.AccessToken, error) {
+	var accessToken model.AccessToken
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&accessToken).Error
+	if err != nil {
+		return nil, err
+	}
+	return &accessToken, nil
+}
+
+// Touch records the client details and the time a token was last used
+func (r *AccessTokenRepository) Touch(ctx context.Context, id uint, ipAddress, userAgent, deviceLabel string) error {
+	return r.db.WithContext(ctx).Model(&model.AccessToken{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"ip_address":   ipAddress,
+		"user_agent":   userAgent,
+		"device_label": deviceLabel,
+		"last_used_at": timezone.Now(),
+	}).Error
+}
+
 func (r *AccessTokenRepository) DeleteExpiredTokens(ctx context.Context) error {
 	// Hard delete expired tokens that are already soft deleted
 	return r.db.WithContext(ctx).Unscoped().Where("expires_at < ? AND deleted_at IS NOT NULL", timezone.Now()).Delete(&model.AccessToken{}).Error
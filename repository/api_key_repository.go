@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go-api/model"
+	"go-api/shared/securetoken"
+	"go-api/shared/timezone"
+
+	"gorm.io/gorm"
+)
+
+// apiKeyDisplayLength is the number of leading characters (including the prefix) kept for display
+const apiKeyDisplayLength = len(model.APIKeyPrefix) + 8
+
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+// Create stores a new hashed API key and returns the raw key which is only known at this point
+func (r *APIKeyRepository) Create(ctx context.Context, userID uint, name string, scopes []string, expiresAt *time.Time) (string, *model.APIKey, error) {
+	secret, err := securetoken.Generate(32)
+	if err != nil {
+		return "", nil, err
+	}
+	key := model.APIKeyPrefix + secret
+
+	apiKey := &model.APIKey{
+		Name:      name,
+		KeyHash:   securetoken.Hash(key),
+		KeyPrefix: key[:apiKeyDisplayLength],
+		Scopes:    strings.Join(scopes, " "),
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	}
+
+	if err := r.db.WithContext(ctx).Create(apiKey).Error; err != nil {
+		return "", nil, err
+	}
+
+	return key, apiKey, nil
+}
+
+// FindByKey looks up a key by the digest of the raw key, including its owner
+func (r *APIKeyRepository) FindByKey(ctx context.Context, key string) (*model.APIKey, error) {
+	var apiKey model.APIKey
+	err := r.db.WithContext(ctx).Preload("User").Preload("User.Role").Where("key_hash = ?", securetoken.Hash(key)).First(&apiKey).Error
+	if err != nil {
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// FindByUser returns all non-revoked keys of a user, newest first
+func (r *APIKeyRepository) FindByUser(ctx context.Context, userID uint) ([]model.APIKey, error) {
+	var apiKeys []model.APIKey
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&apiKeys).Error
+	return apiKeys, err
+}
+
+func (r *APIKeyRepository) FindByIDForUser(ctx context.Context, id, userID uint) (*model.APIKey, error) {
+	var apiKey model.APIKey
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&apiKey).Error
+	if err != nil {
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+func (r *APIKeyRepository) UpdateName(ctx context.Context, id uint, name string) error {
+	return r.db.WithContext(ctx).Model(&model.APIKey{}).Where("id = ?", id).Update("name", name).Error
+}
+
+// Delete revokes a key of the given user.
+// It returns gorm.ErrRecordNotFound if the key does not exist or belongs to someone else.
+func (r *APIKeyRepository) Delete(ctx context.Context, id, userID uint) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&model.APIKey{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteAllForUser revokes every key of a user
+func (r *APIKeyRepository) DeleteAllForUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.APIKey{}).Error
+}
+
+func (r *APIKeyRepository) Touch(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&model.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", timezone.Now()).Error
+}
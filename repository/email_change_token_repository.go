@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-api/model"
+	"go-api/shared/securetoken"
+	"go-api/shared/timezone"
+
+	"gorm.io/gorm"
+)
+
+type EmailChangeTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailChangeTokenRepository(db *gorm.DB) *EmailChangeTokenRepository {
+	return &EmailChangeTokenRepository{
+		db: db,
+	}
+}
+
+// Create stores a pending email change and returns the raw confirmation token.
+// Only the SHA-256 digest of the token is stored in the database.
+func (r *EmailChangeTokenRepository) Create(ctx context.Context, userID uint, newEmail string, expiresIn time.Duration) (string, *model.EmailChangeToken, error) {
+	token, err := securetoken.Generate(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	changeToken := &model.EmailChangeToken{
+		TokenHash: securetoken.Hash(token),
+		UserID:    userID,
+		NewEmail:  newEmail,
+		ExpiresAt: timezone.Now().Add(expiresIn),
+	}
+
+	if err := r.db.WithContext(ctx).Create(changeToken).Error; err != nil {
+		return "", nil, err
+	}
+
+	return token, changeToken, nil
+}
+
+func (r *EmailChangeTokenRepository) FindByToken(ctx context.Context, token string) (*model.EmailChangeToken, error) {
+	var changeToken model.EmailChangeToken
+
+	err := r.db.WithContext(ctx).Preload("User").Where("token_hash = ?", securetoken.Hash(token)).First(&changeToken).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &changeToken, nil
+}
+
+// MarkUsed flags the token as consumed so it cannot be used again.
+// It returns gorm.ErrRecordNotFound if the token was already used concurrently.
+func (r *EmailChangeTokenRepository) MarkUsed(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&model.EmailChangeToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", timezone.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RevokeAllUserTokens cancels every pending email change of a user
+func (r *EmailChangeTokenRepository) RevokeAllUserTokens(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.EmailChangeToken{}).Error
+}
+
+// CleanupExpiredTokens deletes all expired email change tokens
+func (r *EmailChangeTokenRepository) CleanupExpiredTokens(ctx context.Context) error {
+	return r.db.WithContext(ctx).Unscoped().Where("expires_at < ?", timezone.Now()).Delete(&model.EmailChangeToken{}).Error
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-api/model"
+	"go-api/shared/securetoken"
+	"go-api/shared/timezone"
+
+	"gorm.io/gorm"
+)
+
+type EmailVerificationTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailVerificationTokenRepository(db *gorm.DB) *EmailVerificationTokenRepository {
+	return &EmailVerificationTokenRepository{
+		db: db,
+	}
+}
+
+// Create issues a new email verification token for the user and returns the raw token.
+// Only the SHA-256 digest of the token is stored in the database.
+func (r *EmailVerificationTokenRepository) Create(ctx context.Context, userID uint, expiresIn time.Duration) (string, *model.EmailVerificationToken, error) {
+	token, err := securetoken.Generate(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	verificationToken := &model.EmailVerificationToken{
+		TokenHash: securetoken.Hash(token),
+		UserID:    userID,
+		ExpiresAt: timezone.Now().Add(expiresIn),
+	}
+
+	if err := r.db.WithContext(ctx).Create(verificationToken).Error; err != nil {
+		return "", nil, err
+	}
+
+	return token, verificationToken, nil
+}
+
+func (r *EmailVerificationTokenRepository) FindByToken(ctx context.Context, token string) (*model.EmailVerificationToken, error) {
+	var verificationToken model.EmailVerificationToken
+
+	err := r.db.WithContext(ctx).Preload("User").Where("token_hash = ?", securetoken.Hash(token)).First(&verificationToken).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &verificationToken, nil
+}
+
+// MarkUsed flags the token as consumed so it cannot be used again.
+// It returns gorm.ErrRecordNotFound if the token was already used concurrently.
+func (r *EmailVerificationTokenRepository) MarkUsed(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&model.EmailVerificationToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", timezone.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RevokeAllUserTokens invalidates every outstanding email verification token for a user
+func (r *EmailVerificationTokenRepository) RevokeAllUserTokens(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.EmailVerificationToken{}).Error
+}
+
+// CleanupExpiredTokens deletes all expired email verification tokens
+func (r *EmailVerificationTokenRepository) CleanupExpiredTokens(ctx context.Context) error {
+	return r.db.WithContext(ctx).Unscoped().Where("expires_at < ?", timezone.Now()).Delete(&model.EmailVerificationToken{}).Error
+}
+
+// FindLatestByUser returns the most recently issued verification token for a user, used for resend throttling
+func (r *EmailVerificationTokenRepository) FindLatestByUser(ctx context.Context, userID uint) (*model.EmailVerificationToken, error) {
+	var verificationToken model.EmailVerificationToken
+
+	err := r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID).Order("created_at DESC").First(&verificationToken).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &verificationToken, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-api/model"
+	"go-api/shared/securetoken"
+	"go-api/shared/timezone"
+
+	"gorm.io/gorm"
+)
+
+type MagicLinkTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewMagicLinkTokenRepository(db *gorm.DB) *MagicLinkTokenRepository {
+	return &MagicLinkTokenRepository{
+		db: db,
+	}
+}
+
+// Create issues a new login token for the user and returns the raw token.
+// Only the SHA-256 digest of the token is stored in the database.
+func (r *MagicLinkTokenRepository) Create(ctx context.Context, userID uint, expiresIn time.Duration) (string, *model.MagicLinkToken, error) {
+	token, err := securetoken.Generate(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	loginToken := &model.MagicLinkToken{
+		TokenHash: securetoken.Hash(token),
+		UserID:    userID,
+		ExpiresAt: timezone.Now().Add(expiresIn),
+	}
+
+	if err := r.db.WithContext(ctx).Create(loginToken).Error; err != nil {
+		return "", nil, err
+	}
+
+	return token, loginToken, nil
+}
+
+func (r *MagicLinkTokenRepository) FindByToken(ctx context.Context, token string) (*model.MagicLinkToken, error) {
+	var loginToken model.MagicLinkToken
+
+	err := r.db.WithContext(ctx).Preload("User.Role").Where("token_hash = ?", securetoken.Hash(token)).First(&loginToken).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &loginToken, nil
+}
+
+// MarkUsed flags the token as consumed so it cannot be used again.
+// It returns gorm.ErrRecordNotFound if the token was already used concurrently.
+func (r *MagicLinkTokenRepository) MarkUsed(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&model.MagicLinkToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", timezone.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RevokeAllUserTokens invalidates every outstanding login token for a user
+func (r *MagicLinkTokenRepository) RevokeAllUserTokens(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.MagicLinkToken{}).Error
+}
+
+// CleanupExpiredTokens deletes all expired login tokens
+func (r *MagicLinkTokenRepository) CleanupExpiredTokens(ctx context.Context) error {
+	return r.db.WithContext(ctx).Unscoped().Where("expires_at < ?", timezone.Now()).Delete(&model.MagicLinkToken{}).Error
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-api/model"
+	"go-api/shared/securetoken"
+	"go-api/shared/timezone"
+
+	"gorm.io/gorm"
+)
+
+type OAuthAuthorizationCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthAuthorizationCodeRepository(db *gorm.DB) *OAuthAuthorizationCodeRepository {
+	return &OAuthAuthorizationCodeRepository{
+		db: db,
+	}
+}
+
+// Create stores a new authorization code and returns the raw code.
+// Only the SHA-256 digest of the code is stored in the database.
+func (r *OAuthAuthorizationCodeRepository) Create(ctx context.Context, authCode *model.OAuthAuthorizationCode, expiresIn time.Duration) (string, error) {
+	code, err := securetoken.Generate(32)
+	if err != nil {
+		return "", err
+	}
+
+	grantID, err := securetoken.Generate(16)
+	if err != nil {
+		return "", err
+	}
+
+	authCode.CodeHash = securetoken.Hash(code)
+	authCode.GrantID = grantID
+	authCode.ExpiresAt = timezone.Now().Add(expiresIn)
+
+	if err := r.db.WithContext(ctx).Create(authCode).Error; err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// FindByCode finds a code including used ones, so a replayed code can be detected
+func (r *OAuthAuthorizationCodeRepository) FindByCode(ctx context.Context, code string) (*model.OAuthAuthorizationCode, error) {
+	var authCode model.OAuthAuthorizationCode
+	err := r.db.WithContext(ctx).Preload("User").Preload("User.Role").Where("code_hash = ?", securetoken.Hash(code)).First(&authCode).Error
+	if err != nil {
+		return nil, err
+	}
+	return &authCode, nil
+}
+
+// MarkUsed flags the code as exchanged so it cannot be used again.
+// It returns gorm.ErrRecordNotFound if the code was already used concurrently.
+func (r *OAuthAuthorizationCodeRepository) MarkUsed(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&model.OAuthAuthorizationCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", timezone.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CleanupExpired deletes all expired authorization codes
+func (r *OAuthAuthorizationCodeRepository) CleanupExpired(ctx context.Context) error {
+	return r.db.WithContext(ctx).Unscoped().Where("expires_at < ?", timezone.Now()).Delete(&model.OAuthAuthorizationCode{}).Error
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+
+	"go-api/model"
+	"go-api/shared/securetoken"
+
+	"gorm.io/gorm"
+)
+
+type OAuthClientRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthClientRepository(db *gorm.DB) *OAuthClientRepository {
+	return &OAuthClientRepository{
+		db: db,
+	}
+}
+
+// Create registers a client with a generated client ID. Confidential clients also get a
+// secret which is returned once, only its SHA-256 digest is stored.
+func (r *OAuthClientRepository) Create(ctx context.Context, client *model.OAuthClient) (string, error) {
+	clientID, err := securetoken.Generate(16)
+	if err != nil {
+		return "", err
+	}
+	client.ClientID = clientID
+
+	var secret string
+	if !client.Public {
+		secret, err = securetoken.Generate(32)
+		if err != nil {
+			return "", err
+		}
+		client.ClientSecretHash = securetoken.Hash(secret)
+	}
+
+	if err := r.db.WithContext(ctx).Create(client).Error; err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+func (r *OAuthClientRepository) FindByClientID(ctx context.Context, clientID string) (*model.OAuthClient, error) {
+	var client model.OAuthClient
+	err := r.db.WithContext(ctx).Where("client_id = ?", clientID).First(&client).Error
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (r *OAuthClientRepository) FindByID(ctx context.Context, id uint) (*model.OAuthClient, error) {
+	var client model.OAuthClient
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&client).Error
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (r *OAuthClientRepository) List(ctx context.Context) ([]model.OAuthClient, error) {
+	var clients []model.OAuthClient
+	err := r.db.WithContext(ctx).Order("created_at DESC").Find(&clients).Error
+	return clients, err
+}
+
+// Delete removes a client. It returns gorm.ErrRecordNotFound if the client does not exist.
+func (r *OAuthClientRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&model.OAuthClient{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
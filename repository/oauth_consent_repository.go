@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"go-api/model"
+
+	"gorm.io/gorm"
+)
+
+type OAuthConsentRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthConsentRepository(db *gorm.DB) *OAuthConsentRepository {
+	return &OAuthConsentRepository{
+		db: db,
+	}
+}
+
+func (r *OAuthConsentRepository) FindByUserAndClient(ctx context.Context, userID, clientID uint) (*model.OAuthConsent, error) {
+	var consent model.OAuthConsent
+	err := r.db.WithContext(ctx).Where("user_id = ? AND client_id = ?", userID, clientID).First(&consent).Error
+	if err != nil {
+		return nil, err
+	}
+	return &consent, nil
+}
+
+// Save records the scopes granted to a client, replacing any earlier consent
+func (r *OAuthConsentRepository) Save(ctx context.Context, userID, clientID uint, scopes string) error {
+	consent, err := r.FindByUserAndClient(ctx, userID, clientID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return r.db.WithContext(ctx).Create(&model.OAuthConsent{
+			UserID:   userID,
+			ClientID: clientID,
+			Scopes:   scopes,
+		}).Error
+	}
+
+	return r.db.WithContext(ctx).Model(consent).Update("scopes", scopes).Error
+}
+
+// FindByUser returns the consents of a user with their clients
+func (r *OAuthConsentRepository) FindByUser(ctx context.Context, userID uint) ([]model.OAuthConsent, error) {
+	var consents []model.OAuthConsent
+	err := r.db.WithContext(ctx).Preload("Client").Where("user_id = ?", userID).Order("created_at DESC").Find(&consents).Error
+	return consents, err
+}
+
+func (r *OAuthConsentRepository) FindByIDForUser(ctx context.Context, id, userID uint) (*model.OAuthConsent, error) {
+	var consent model.OAuthConsent
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&consent).Error
+	if err != nil {
+		return nil, err
+	}
+	return &consent, nil
+}
+
+func (r *OAuthConsentRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&model.OAuthConsent{}, id).Error
+}
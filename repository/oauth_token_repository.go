@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-api/model"
+	"go-api/shared/securetoken"
+	"go-api/shared/timezone"
+
+	"gorm.io/gorm"
+)
+
+type OAuthTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthTokenRepository(db *gorm.DB) *OAuthTokenRepository {
+	return &OAuthTokenRepository{
+		db: db,
+	}
+}
+
+// NewGrantID generates the identifier shared by all tokens of one authorization
+func (r *OAuthTokenRepository) NewGrantID() (string, error) {
+	return securetoken.Generate(16)
+}
+
+// Create issues a token and returns the raw value, prefixed by its type.
+// Only the SHA-256 digest of the token is stored in the database.
+func (r *OAuthTokenRepository) Create(ctx context.Context, tokenType string, clientID uint, userID *uint, scopes, grantID string, expiresIn time.Duration) (string, *model.OAuthToken, error) {
+	secret, err := securetoken.Generate(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	prefix := model.OAuthAccessTokenPrefix
+	if tokenType == model.OAuthTokenTypeRefresh {
+		prefix = model.OAuthRefreshTokenPrefix
+	}
+	token := prefix + secret
+
+	oauthToken := &model.OAuthToken{
+		TokenHash: securetoken.Hash(token),
+		TokenType: tokenType,
+		ClientID:  clientID,
+		UserID:    userID,
+		Scopes:    scopes,
+		GrantID:   grantID,
+		ExpiresAt: timezone.Now().Add(expiresIn),
+	}
+
+	if err := r.db.WithContext(ctx).Create(oauthToken).Error; err != nil {
+		return "", nil, err
+	}
+
+	return token, oauthToken, nil
+}
+
+// FindByToken looks a token up by its digest, including its client and user
+func (r *OAuthTokenRepository) FindByToken(ctx context.Context, token string) (*model.OAuthToken, error) {
+	var oauthToken model.OAuthToken
+	err := r.db.WithContext(ctx).Preload("Client").Preload("User").Preload("User.Role").
+		Where("token_hash = ?", securetoken.Hash(token)).First(&oauthToken).Error
+	if err != nil {
+		return nil, err
+	}
+	return &oauthToken, nil
+}
+
+// MarkUsed flags a refresh token as rotated.
+// It returns gorm.ErrRecordNotFound if the token was already used or revoked concurrently.
+func (r *OAuthTokenRepository) MarkUsed(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&model.OAuthToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", timezone.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *OAuthTokenRepository) Revoke(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&model.OAuthToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", timezone.Now()).Error
+}
+
+// RevokeGrant revokes every token issued for one authorization
+func (r *OAuthTokenRepository) RevokeGrant(ctx context.Context, grantID string) error {
+	return r.db.WithContext(ctx).Model(&model.OAuthToken{}).
+		Where("grant_id = ? AND revoked_at IS NULL", grantID).
+		Update("revoked_at", timezone.Now()).Error
+}
+
+// RevokeByUserAndClient revokes every token a user granted to a client
+func (r *OAuthTokenRepository) RevokeByUserAndClient(ctx context.Context, userID, clientID uint) error {
+	return r.db.WithContext(ctx).Model(&model.OAuthToken{}).
+		Where("user_id = ? AND client_id = ? AND revoked_at IS NULL", userID, clientID).
+		Update("revoked_at", timezone.Now()).Error
+}
+
+// RevokeAllForUser revokes every token a user granted to any client
+func (r *OAuthTokenRepository) RevokeAllForUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&model.OAuthToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", timezone.Now()).Error
+}
+
+// CleanupExpired deletes all expired tokens
+func (r *OAuthTokenRepository) CleanupExpired(ctx context.Context) error {
+	return r.db.WithContext(ctx).Unscoped().Where("expires_at < ?", timezone.Now()).Delete(&model.OAuthToken{}).Error
+}
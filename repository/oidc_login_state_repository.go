@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-api/model"
+	"go-api/shared/securetoken"
+	"go-api/shared/timezone"
+
+	"gorm.io/gorm"
+)
+
+type OIDCLoginStateRepository struct {
+	db *gorm.DB
+}
+
+func NewOIDCLoginStateRepository(db *gorm.DB) *OIDCLoginStateRepository {
+	return &OIDCLoginStateRepository{
+		db: db,
+	}
+}
+
+// Create starts a login at a provider and returns the raw state sent along with the user.
+// Only the SHA-256 digest of the state is stored in the database.
+func (r *OIDCLoginStateRepository) Create(ctx context.Context, provider, codeVerifier string, userID *uint, expiresIn time.Duration) (string, *model.OIDCLoginState, error) {
+	state, err := securetoken.Generate(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	nonce, err := securetoken.Generate(16)
+	if err != nil {
+		return "", nil, err
+	}
+
+	loginState := &model.OIDCLoginState{
+		StateHash:    securetoken.Hash(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		UserID:       userID,
+		ExpiresAt:    timezone.Now().Add(expiresIn),
+	}
+
+	if err := r.db.WithContext(ctx).Create(loginState).Error; err != nil {
+		return "", nil, err
+	}
+
+	return state, loginState, nil
+}
+
+func (r *OIDCLoginStateRepository) FindByState(ctx context.Context, state string) (*model.OIDCLoginState, error) {
+	var loginState model.OIDCLoginState
+	err := r.db.WithContext(ctx).Where("state_hash = ?", securetoken.Hash(state)).First(&loginState).Error
+	if err != nil {
+		return nil, err
+	}
+	return &loginState, nil
+}
+
+// MarkUsed flags the state as consumed so a callback cannot be replayed.
+// It returns gorm.ErrRecordNotFound if the state was already used concurrently.
+func (r *OIDCLoginStateRepository) MarkUsed(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&model.OIDCLoginState{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", timezone.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CleanupExpired deletes all expired login states
+func (r *OIDCLoginStateRepository) CleanupExpired(ctx context.Context) error {
+	return r.db.WithContext(ctx).Unscoped().Where("expires_at < ?", timezone.Now()).Delete(&model.OIDCLoginState{}).Error
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"go-api/model"
+	"go-api/tenant"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrganizationMemberRepository manages the members of the active organization. Memberships are
+// tenant scoped, every method except FindMembership needs an organization in ctx.
+type OrganizationMemberRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationMemberRepository(db *gorm.DB) *OrganizationMemberRepository {
+	return &OrganizationMemberRepository{
+		db: db,
+	}
+}
+
+// FindMembership looks up the membership of a user in any organization, it is used to resolve
+// the active organization of a request
+func (r *OrganizationMemberRepository) FindMembership(ctx context.Context, organizationID, userID uint) (*model.OrganizationMember, error) {
+	var member model.OrganizationMember
+	err := r.db.WithContext(tenant.Unscoped(ctx)).Preload("Organization").
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *OrganizationMemberRepository) Create(ctx context.Context, member *model.OrganizationMember) error {
+	return r.db.WithContext(ctx).Create(member).Error
+}
+
+// FindByUserID returns the membership of a user in the active organization
+func (r *OrganizationMemberRepository) FindByUserID(ctx context.Context, userID uint) (*model.OrganizationMember, error) {
+	var member model.OrganizationMember
+	err := r.db.WithContext(ctx).Preload("User").Where("user_id = ?", userID).First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// List returns the members of the active organization
+func (r *OrganizationMemberRepository) List(ctx context.Context) ([]model.OrganizationMember, error) {
+	var members []model.OrganizationMember
+	err := r.db.WithContext(ctx).Preload("User").Order("created_at ASC").Find(&members).Error
+	return members, err
+}
+
+// CountByRoleForUpdate counts the members of the active organization holding a role and locks
+// their rows until the transaction ends, so concurrent role changes are checked one after another.
+// It must run inside a transaction.
+func (r *OrganizationMemberRepository) CountByRoleForUpdate(ctx context.Context, role string) (int64, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&model.OrganizationMember{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("role = ?", role).
+		Pluck("id", &ids).Error
+	return int64(len(ids)), err
+}
+
+// UpdateRole changes the role of a member of the active organization
+func (r *OrganizationMemberRepository) UpdateRole(ctx context.Context, userID uint, role string) error {
+	result := r.db.WithContext(ctx).Model(&model.OrganizationMember{}).Where("user_id = ?", userID).Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete removes a user from the active organization, permanently so they can be added again
+func (r *OrganizationMemberRepository) Delete(ctx context.Context, userID uint) error {
+	result := r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID).Delete(&model.OrganizationMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
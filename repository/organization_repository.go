@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"go-api/model"
+
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) *OrganizationRepository {
+	return &OrganizationRepository{
+		db: db,
+	}
+}
+
+func (r *OrganizationRepository) Create(ctx context.Context, organization *model.Organization) error {
+	return r.db.WithContext(ctx).Create(organization).Error
+}
+
+func (r *OrganizationRepository) FindByID(ctx context.Context, id uint) (*model.Organization, error) {
+	var organization model.Organization
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&organization).Error
+	if err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+// ListForUser returns the organizations the user is a member of
+func (r *OrganizationRepository) ListForUser(ctx context.Context, userID uint) ([]model.Organization, error) {
+	var organizations []model.Organization
+	err := r.db.WithContext(ctx).
+		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ? AND organization_members.deleted_at IS NULL", userID).
+		Order("organizations.name ASC").
+		Find(&organizations).Error
+	return organizations, err
+}
+
+// SlugExists reports whether an organization uses the slug, including deleted ones
+func (r *OrganizationRepository) SlugExists(ctx context.Context, slug string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&model.Organization{}).Where("slug = ?", slug).Count(&count).Error
+	return count > 0, err
+}
+
+// Update applies the given column changes to an organization
+func (r *OrganizationRepository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).Model(&model.Organization{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete permanently deletes an organization, its memberships are removed by cascade
+func (r *OrganizationRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Unscoped().Delete(&model.Organization{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"go-api/model"
+
+	"gorm.io/gorm"
+)
+
+type PasswordHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordHistoryRepository(db *gorm.DB) *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{
+		db: db,
+	}
+}
+
+func (r *PasswordHistoryRepository) Create(ctx context.Context, userID uint, passwordHash string) error {
+	return r.db.WithContext(ctx).Create(&model.PasswordHistory{
+		UserID:       userID,
+		PasswordHash: passwordHash,
+	}).Error
+}
+
+// FindRecent returns the latest password hashes of a user, newest first
+func (r *PasswordHistoryRepository) FindRecent(ctx context.Context, userID uint, limit int) ([]model.PasswordHistory, error) {
+	var history []model.PasswordHistory
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC, id DESC").Limit(limit).Find(&history).Error
+	return history, err
+}
+
+// Prune deletes all but the latest keep password hashes of a user
+func (r *PasswordHistoryRepository) Prune(ctx context.Context, userID uint, keep int) error {
+	recent := r.db.Model(&model.PasswordHistory{}).Select("id").
+		Where("user_id = ?", userID).Order("created_at DESC, id DESC").Limit(keep)
+
+	return r.db.WithContext(ctx).Unscoped().
+		Where("user_id = ? AND id NOT IN (?)", userID, recent).
+		Delete(&model.PasswordHistory{}).Error
+}
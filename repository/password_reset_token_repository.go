@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-api/model"
+	"go-api/shared/securetoken"
+	"go-api/shared/timezone"
+
+	"gorm.io/gorm"
+)
+
+type PasswordResetTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetTokenRepository(db *gorm.DB) *PasswordResetTokenRepository {
+	return &PasswordResetTokenRepository{
+		db: db,
+	}
+}
+
+// Create issues a new reset token for the user and returns the raw token.
+// Only the SHA-256 digest of the token is stored in the database.
+func (r *PasswordResetTokenRepository) Create(ctx context.Context, userID uint, expiresIn time.Duration) (string, *model.PasswordResetToken, error) {
+	token, err := securetoken.Generate(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	resetToken := &model.PasswordResetToken{
+		TokenHash: securetoken.Hash(token),
+		UserID:    userID,
+		ExpiresAt: timezone.Now().Add(expiresIn),
+	}
+
+	if err := r.db.WithContext(ctx).Create(resetToken).Error; err != nil {
+		return "", nil, err
+	}
+
+	return token, resetToken, nil
+}
+
+func (r *PasswordResetTokenRepository) FindByToken(ctx context.Context, token string) (*model.PasswordResetToken, error) {
+	var resetToken model.PasswordResetToken
+
+	err := r.db.WithContext(ctx).Preload("User").Where("token_hash = ?", securetoken.Hash(token)).First(&resetToken).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &resetToken, nil
+}
+
+// MarkUsed flags the token as consumed so it cannot be used again.
+// It returns gorm.ErrRecordNotFound if the token was already used concurrently.
+func (r *PasswordResetTokenRepository) MarkUsed(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&model.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", timezone.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RevokeAllUserTokens invalidates every outstanding reset token for a user
+func (r *PasswordResetTokenRepository) RevokeAllUserTokens(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.PasswordResetToken{}).Error
+}
+
+// CleanupExpiredTokens deletes all expired reset tokens
+func (r *PasswordResetTokenRepository) CleanupExpiredTokens(ctx context.Context) error {
+	return r.db.WithContext(ctx).Unscoped().Where("expires_at < ?", timezone.Now()).Delete(&model.PasswordResetToken{}).Error
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+
+	"go-api/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PermissionRepository struct {
+	db *gorm.DB
+}
+
+func NewPermissionRepository(db *gorm.DB) *PermissionRepository {
+	return &PermissionRepository{
+		db: db,
+	}
+}
+
+// FindAll returns every permission ordered by code
+func (r *PermissionRepository) FindAll(ctx context.Context) ([]model.Permission, error) {
+	var permissions []model.Permission
+	err := r.db.WithContext(ctx).Order("code ASC").Find(&permissions).Error
+	return permissions, err
+}
+
+// FindByCodes returns the permissions with the given codes
+func (r *PermissionRepository) FindByCodes(ctx context.Context, codes []string) ([]model.Permission, error) {
+	var permissions []model.Permission
+	if len(codes) == 0 {
+		return permissions, nil
+	}
+	err := r.db.WithContext(ctx).Where("code IN ?", codes).Find(&permissions).Error
+	return permissions, err
+}
+
+// FindCodesByRole returns the codes of the permissions granted to a role
+func (r *PermissionRepository) FindCodesByRole(ctx context.Context, roleID uint) ([]string, error) {
+	var codes []string
+	err := r.db.WithContext(ctx).Model(&model.Permission{}).
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Where("role_permissions.role_id = ?", roleID).
+		Pluck("permissions.code", &codes).Error
+	return codes, err
+}
+
+// Upsert creates the given permissions and refreshes the description of existing ones
+func (r *PermissionRepository) Upsert(ctx context.Context, permissions []model.Permission) error {
+	if len(permissions) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"description", "updated_at"}),
+	}).Create(&permissions).Error
+}
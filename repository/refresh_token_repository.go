@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-api/model"
+	"go-api/shared/securetoken"
+	"go-api/shared/timezone"
+
+	"gorm.io/gorm"
+)
+
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db: db,
+	}
+}
+
+// NewFamilyID generates the identifier shared by all tokens of one rotation chain
+func (r *RefreshTokenRepository) NewFamilyID() (string, error) {
+	return securetoken.Generate(16)
+}
+
+// Create issues a new refresh token in the given family and returns the raw token.
+// Only the SHA-256 digest of the token is stored in the database.
+func (r *RefreshTokenRepository) Create(ctx context.Context, userID uint, familyID string, parentID, accessTokenID *uint, sessionStartedAt time.Time, expiresIn time.Duration) (string, *model.RefreshToken, error) {
+	token, err := securetoken.Generate(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	refreshToken := &model.RefreshToken{
+		TokenHash:        securetoken.Hash(token),
+		FamilyID:         familyID,
+		ParentID:         parentID,
+		UserID:           userID,
+		AccessTokenID:    accessTokenID,
+		ExpiresAt:        timezone.Now().Add(expiresIn),
+		SessionStartedAt: sessionStartedAt,
+	}
+
+	if err := r.db.WithContext(ctx).Create(refreshToken).Error; err != nil {
+		return "", nil, err
+	}
+
+	return token, refreshToken, nil
+}
+
+func (r *RefreshTokenRepository) FindByToken(ctx context.Context, token string) (*model.RefreshToken, error) {
+	var refreshToken model.RefreshToken
+
+	err := r.db.WithContext(ctx).Where("token_hash = ?", securetoken.Hash(token)).First(&refreshToken).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &refreshToken, nil
+}
+
+// FindActiveByUser retrieves the exchangeable token of every live family of a user,
+// which is exactly one token per session
+func (r *RefreshTokenRepository) FindActiveByUser(ctx context.Context, userID uint) ([]model.RefreshToken, error) {
+	var refreshTokens []model.RefreshToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND used_at IS NULL AND revoked_at IS NULL AND expires_at > ?", userID, timezone.Now()).
+		Order("created_at DESC").
+		Find(&refreshTokens).Error
+	return refreshTokens, err
+}
+
+// FindByIDForUser retrieves a token by ID only if it belongs to the given user
+func (r *RefreshTokenRepository) FindByIDForUser(ctx context.Context, id, userID uint) (*model.RefreshToken, error) {
+	var refreshToken model.RefreshToken
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&refreshToken).Error
+	if err != nil {
+		return nil, err
+	}
+	return &refreshToken, nil
+}
+
+// MarkUsed flags the token as exchanged.
+// It returns gorm.ErrRecordNotFound if the token was used concurrently.
+func (r *RefreshTokenRepository) MarkUsed(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&model.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", timezone.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RevokeFamily revokes every token of a rotation family
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	return r.db.WithContext(ctx).Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", timezone.Now()).Error
+}
+
+// RevokeAllUserTokens revokes all refresh tokens of a user
+func (r *RefreshTokenRepository) RevokeAllUserTokens(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", timezone.Now()).Error
+}
+
+// CleanupExpiredTokens deletes all expired refresh tokens
+func (r *RefreshTokenRepository) CleanupExpiredTokens(ctx context.Context) error {
+	return r.db.WithContext(ctx).Unscoped().Where("expires_at < ?", timezone.Now()).Delete(&model.RefreshToken{}).Error
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-api/model"
+	"go-api/shared/timezone"
+
+	"gorm.io/gorm"
+)
+
+type RevokedTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRevokedTokenRepository(db *gorm.DB) *RevokedTokenRepository {
+	return &RevokedTokenRepository{
+		db: db,
+	}
+}
+
+// RevokeJTI adds a single token to the denylist until it expires
+func (r *RevokedTokenRepository) RevokeJTI(ctx context.Context, userID uint, jti string, expiresAt time.Time) error {
+	entry := &model.RevokedToken{
+		JTI:       &jti,
+		UserID:    userID,
+		RevokedAt: timezone.Now(),
+		ExpiresAt: expiresAt,
+	}
+	return r.db.WithContext(ctx).Where(model.RevokedToken{JTI: &jti}).FirstOrCreate(entry).Error
+}
+
+// RevokeSession denylists every token issued for a session.
+// expiresAt must be at least the expiry of the longest lived token of that session.
+func (r *RevokedTokenRepository) RevokeSession(ctx context.Context, userID uint, sessionID string, expiresAt time.Time) error {
+	entry := &model.RevokedToken{
+		SessionID: &sessionID,
+		UserID:    userID,
+		RevokedAt: timezone.Now(),
+		ExpiresAt: expiresAt,
+	}
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// RevokeUser denylists every token of a user issued before now.
+// The cutoff is truncated to the millisecond precision of the iat claim so a token issued
+// right after the revocation (e.g. on password change) stays valid.
+func (r *RevokedTokenRepository) RevokeUser(ctx context.Context, userID uint, expiresAt time.Time) error {
+	entry := &model.RevokedToken{
+		UserID:    userID,
+		RevokedAt: timezone.Now().Truncate(time.Millisecond),
+		ExpiresAt: expiresAt,
+	}
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+// IsRevoked checks the denylist for a token by its jti, its session and the user wide cutoff
+func (r *RevokedTokenRepository) IsRevoked(ctx context.Context, userID uint, jti, sessionID string, issuedAt time.Time) (bool, error) {
+	conditions := r.db.Where("jti = ?", jti).
+		Or("user_id = ? AND jti IS NULL AND session_id IS NULL AND revoked_at > ?", userID, issuedAt)
+	if sessionID != "" {
+		conditions = conditions.Or("session_id = ?", sessionID)
+	}
+
+	query := r.db.WithContext(ctx).Model(&model.RevokedToken{}).
+		Where("expires_at > ?", timezone.Now()).
+		Where(conditions)
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CleanupExpired deletes denylist entries whose tokens have expired anyway
+func (r *RevokedTokenRepository) CleanupExpired(ctx context.Context) error {
+	return r.db.WithContext(ctx).Unscoped().Where("expires_at < ?", timezone.Now()).Delete(&model.RevokedToken{}).Error
+}
@@ -8,6 +8,8 @@ import (
 	"gorm.io/gorm"
 )
 
+var ErrRoleNotFound = errors.New("role not found")
+
 type RoleRepository struct {
 	db *gorm.DB
 }
@@ -24,7 +26,7 @@ func (r *RoleRepository) FindByCode(ctx context.Context, code string) (*model.Ro
 
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.New("role not found")
+			return nil, ErrRoleNotFound
 		}
 		return nil, err
 	}
@@ -39,7 +41,7 @@ func (r *RoleRepository) FindByID(ctx context.Context, id uint) (*model.Role, er
 
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, errors.New("role not found")
+			return nil, ErrRoleNotFound
 		}
 		return nil, err
 	}
@@ -47,3 +49,75 @@ func (r *RoleRepository) FindByID(ctx context.Context, id uint) (*model.Role, er
 	return &role, nil
 }
 
+// FindByIDWithPermissions retrieves a role by ID together with the permissions granted to it
+func (r *RoleRepository) FindByIDWithPermissions(ctx context.Context, id uint) (*model.Role, error) {
+	var role model.Role
+	err := r.db.WithContext(ctx).Preload("Permissions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("code ASC")
+	}).Where("id = ?", id).First(&role).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+// List returns every role with its permissions, ordered by code
+func (r *RoleRepository) List(ctx context.Context) ([]model.Role, error) {
+	var roles []model.Role
+	err := r.db.WithContext(ctx).Preload("Permissions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("code ASC")
+	}).Order("code ASC").Find(&roles).Error
+	return roles, err
+}
+
+// CodeExists reports whether a role with the given code exists, including deleted ones
+func (r *RoleRepository) CodeExists(ctx context.Context, code string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&model.Role{}).Where("code = ?", code).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *RoleRepository) Create(ctx context.Context, role *model.Role) error {
+	return r.db.WithContext(ctx).Create(role).Error
+}
+
+// Update applies the given column changes to a role
+func (r *RoleRepository) Update(ctx context.Context, id uint, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).Model(&model.Role{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRoleNotFound
+	}
+	return nil
+}
+
+// ReplacePermissions replaces every permission granted to a role
+func (r *RoleRepository) ReplacePermissions(ctx context.Context, role *model.Role, permissions []model.Permission) error {
+	return r.db.WithContext(ctx).Model(role).Association("Permissions").Replace(permissions)
+}
+
+// CountUsers counts the users holding a role, including deleted ones that can still be restored
+func (r *RoleRepository) CountUsers(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&model.User{}).Where("role_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// Delete permanently deletes a role so its code can be reused, its permission grants are removed by cascade
+func (r *RoleRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Unscoped().Delete(&model.Role{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRoleNotFound
+	}
+	return nil
+}
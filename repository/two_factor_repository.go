@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-api/model"
+	"go-api/shared/securetoken"
+	"go-api/shared/timezone"
+
+	"gorm.io/gorm"
+)
+
+// TwoFactorRepository manages TOTP credentials, recovery codes and login challenges
+type TwoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRepository(db *gorm.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{
+		db: db,
+	}
+}
+
+func (r *TwoFactorRepository) FindCredentialByUser(ctx context.Context, userID uint) (*model.TwoFactorCredential, error) {
+	var credential model.TwoFactorCredential
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&credential).Error
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// SaveCredential replaces any pending or existing credential of the user with an unconfirmed one
+func (r *TwoFactorRepository) SaveCredential(ctx context.Context, userID uint, secret string) (*model.TwoFactorCredential, error) {
+	if err := r.DeleteCredential(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	credential := &model.TwoFactorCredential{
+		UserID: userID,
+		Secret: secret,
+	}
+	if err := r.db.WithContext(ctx).Create(credential).Error; err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
+
+func (r *TwoFactorRepository) ConfirmCredential(ctx context.Context, id uint, step int64) error {
+	return r.db.WithContext(ctx).Model(&model.TwoFactorCredential{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"confirmed_at":   timezone.Now(),
+		"last_used_step": step,
+	}).Error
+}
+
+// UseStep records the time step of an accepted code.
+// It returns gorm.ErrRecordNotFound if that step (or a later one) was already used.
+func (r *TwoFactorRepository) UseStep(ctx context.Context, id uint, step int64) error {
+	result := r.db.WithContext(ctx).Model(&model.TwoFactorCredential{}).
+		Where("id = ? AND last_used_step < ?", id, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteCredential permanently removes the credential so the secret does not linger
+func (r *TwoFactorRepository) DeleteCredential(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID).Delete(&model.TwoFactorCredential{}).Error
+}
+
+// ReplaceRecoveryCodes discards the existing recovery codes and stores digests of the new ones
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, codes []string) error {
+	if err := r.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return err
+	}
+
+	records := make([]model.TwoFactorRecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		records = append(records, model.TwoFactorRecoveryCode{
+			UserID:   userID,
+			CodeHash: securetoken.Hash(code),
+		})
+	}
+	return r.db.WithContext(ctx).Create(&records).Error
+}
+
+// UseRecoveryCode consumes a recovery code of the user.
+// It returns gorm.ErrRecordNotFound if the code does not exist or was already used.
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID uint, code string) error {
+	result := r.db.WithContext(ctx).Model(&model.TwoFactorRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, securetoken.Hash(code)).
+		Update("used_at", timezone.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CountUnusedRecoveryCodes returns how many recovery codes the user has left
+func (r *TwoFactorRepository) CountUnusedRecoveryCodes(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.TwoFactorRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *TwoFactorRepository) DeleteRecoveryCodes(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID).Delete(&model.TwoFactorRecoveryCode{}).Error
+}
+
+// CreateChallenge issues a login challenge and returns the raw challenge token
+func (r *TwoFactorRepository) CreateChallenge(ctx context.Context, userID uint, expiresIn time.Duration) (string, *model.TwoFactorChallenge, error) {
+	token, err := securetoken.Generate(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	challenge := &model.TwoFactorChallenge{
+		TokenHash: securetoken.Hash(token),
+		UserID:    userID,
+		ExpiresAt: timezone.Now().Add(expiresIn),
+	}
+	if err := r.db.WithContext(ctx).Create(challenge).Error; err != nil {
+		return "", nil, err
+	}
+
+	return token, challenge, nil
+}
+
+func (r *TwoFactorRepository) FindChallengeByToken(ctx context.Context, token string) (*model.TwoFactorChallenge, error) {
+	var challenge model.TwoFactorChallenge
+	err := r.db.WithContext(ctx).Preload("User").Preload("User.Role").Where("token_hash = ?", securetoken.Hash(token)).First(&challenge).Error
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// IncrementChallengeAttempts counts a failed code submission against the challenge
+func (r *TwoFactorRepository) IncrementChallengeAttempts(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&model.TwoFactorChallenge{}).Where("id = ?", id).
+		UpdateColumn("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+// MarkChallengeUsed completes the challenge.
+// It returns gorm.ErrRecordNotFound if it was already completed concurrently.
+func (r *TwoFactorRepository) MarkChallengeUsed(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&model.TwoFactorChallenge{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", timezone.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CleanupExpiredChallenges deletes all expired login challenges
+func (r *TwoFactorRepository) CleanupExpiredChallenges(ctx context.Context) error {
+	return r.db.WithContext(ctx).Unscoped().Where("expires_at < ?", timezone.Now()).Delete(&model.TwoFactorChallenge{}).Error
+}
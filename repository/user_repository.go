@@ -3,8 +3,12 @@ package repository
 import (
 	"context"
 	"go-api/model"
+	"go-api/shared/timezone"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository struct {
@@ -19,7 +23,7 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
-	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	err := r.db.WithContext(ctx).Preload("Role").Where("email = ?", email).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -28,7 +32,7 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*model.
 
 func (r *UserRepository) FindByID(ctx context.Context, id uint) (*model.User, error) {
 	var user model.User
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&user).Error
+	err := r.db.WithContext(ctx).Preload("Role").Where("id = ?", id).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -38,3 +42,119 @@ func (r *UserRepository) FindByID(ctx context.Context, id uint) (*model.User, er
 func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
 	return r.db.WithContext(ctx).Create(user).Error
 }
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, userID uint, hashedPassword string) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Update("password", hashedPassword).Error
+}
+
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Update("email_verified_at", timezone.Now()).Error
+}
+
+// RecordFailedLogin atomically increments the failed login counter and returns the new value
+func (r *UserRepository) RecordFailedLogin(ctx context.Context, userID uint) (int, error) {
+	user := model.User{}
+	user.ID = userID
+	err := r.db.WithContext(ctx).Model(&user).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "failed_login_attempts"}}}).
+		UpdateColumn("failed_login_attempts", gorm.Expr("failed_login_attempts + 1")).Error
+	if err != nil {
+		return 0, err
+	}
+	return user.FailedLoginAttempts, nil
+}
+
+func (r *UserRepository) LockUntil(ctx context.Context, userID uint, until time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).UpdateColumn("locked_until", until).Error
+}
+
+// ResetFailedLogins clears the failed login counter and any active lockout
+func (r *UserRepository) ResetFailedLogins(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).UpdateColumns(map[string]interface{}{
+		"failed_login_attempts": 0,
+		"locked_until":          nil,
+	}).Error
+}
+
+func (r *UserRepository) UpdateName(ctx context.Context, userID uint, name string) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Update("name", name).Error
+}
+
+// EmailExists checks if an email is taken, including by soft deleted users that still hold the unique index
+func (r *UserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&model.User{}).Where("LOWER(email) = LOWER(?)", email).Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateEmail replaces the email address and marks it verified, since it was confirmed from the new mailbox
+func (r *UserRepository) UpdateEmail(ctx context.Context, userID uint, email string) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"email":             email,
+		"email_verified_at": timezone.Now(),
+	}).Error
+}
+
+// ScheduleDeletion marks the account for purging at the given time
+func (r *UserRepository) ScheduleDeletion(ctx context.Context, userID uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Update("deletion_scheduled_at", at).Error
+}
+
+// CancelDeletion keeps an account that was scheduled for purging
+func (r *UserRepository) CancelDeletion(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Update("deletion_scheduled_at", nil).Error
+}
+
+// FindDueForPurge returns accounts whose scheduled deletion time has passed, oldest first
+func (r *UserRepository) FindDueForPurge(ctx context.Context, limit int) ([]model.User, error) {
+	var users []model.User
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", timezone.Now()).
+		Order("deletion_scheduled_at ASC").
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+// Purge permanently deletes a user, related rows are removed by the foreign key cascades
+func (r *UserRepository) Purge(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Unscoped().Where("id = ?", userID).Delete(&model.User{}).Error
+}
+
+// List returns a page of users with their role, optionally filtered by name or email and role
+func (r *UserRepository) List(ctx context.Context, search string, roleID uint, offset, limit int) ([]model.User, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.User{})
+	if search != "" {
+		pattern := "%" + strings.ToLower(search) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ?", pattern, pattern)
+	}
+	if roleID != 0 {
+		query = query.Where("role_id = ?", roleID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []model.User
+	err := query.Preload("Role").Order("id ASC").Offset(offset).Limit(limit).Find(&users).Error
+	return users, total, err
+}
+
+// Update changes the given columns of a user
+func (r *UserRepository) Update(ctx context.Context, userID uint, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Updates(updates).Error
+}
+
+// Delete soft deletes a user
+func (r *UserRepository) Delete(ctx context.Context, userID uint) error {
+	result := r.db.WithContext(ctx).Where("id = ?", userID).Delete(&model.User{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
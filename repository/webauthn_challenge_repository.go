@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"go-api/model"
+	"go-api/shared/timezone"
+
+	"gorm.io/gorm"
+)
+
+type WebAuthnChallengeRepository struct {
+	db *gorm.DB
+}
+
+func NewWebAuthnChallengeRepository(db *gorm.DB) *WebAuthnChallengeRepository {
+	return &WebAuthnChallengeRepository{
+		db: db,
+	}
+}
+
+// Create stores the session data of a ceremony until the authenticator answers the challenge
+func (r *WebAuthnChallengeRepository) Create(ctx context.Context, ceremony, challenge, sessionData string, userID *uint, expiresIn time.Duration) error {
+	return r.db.WithContext(ctx).Create(&model.WebAuthnChallenge{
+		Challenge:   challenge,
+		Ceremony:    ceremony,
+		UserID:      userID,
+		SessionData: sessionData,
+		ExpiresAt:   timezone.Now().Add(expiresIn),
+	}).Error
+}
+
+func (r *WebAuthnChallengeRepository) FindByChallenge(ctx context.Context, ceremony, challenge string) (*model.WebAuthnChallenge, error) {
+	var webAuthnChallenge model.WebAuthnChallenge
+	err := r.db.WithContext(ctx).Where("challenge = ? AND ceremony = ?", challenge, ceremony).First(&webAuthnChallenge).Error
+	if err != nil {
+		return nil, err
+	}
+	return &webAuthnChallenge, nil
+}
+
+// MarkUsed flags the challenge as answered so a signed response cannot be replayed.
+// It returns gorm.ErrRecordNotFound if the challenge was already used concurrently.
+func (r *WebAuthnChallengeRepository) MarkUsed(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Model(&model.WebAuthnChallenge{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", timezone.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CleanupExpired deletes all expired challenges
+func (r *WebAuthnChallengeRepository) CleanupExpired(ctx context.Context) error {
+	return r.db.WithContext(ctx).Unscoped().Where("expires_at < ?", timezone.Now()).Delete(&model.WebAuthnChallenge{}).Error
+}
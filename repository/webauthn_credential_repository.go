@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+
+	"go-api/model"
+	"go-api/shared/timezone"
+
+	"gorm.io/gorm"
+)
+
+type WebAuthnCredentialRepository struct {
+	db *gorm.DB
+}
+
+func NewWebAuthnCredentialRepository(db *gorm.DB) *WebAuthnCredentialRepository {
+	return &WebAuthnCredentialRepository{
+		db: db,
+	}
+}
+
+func (r *WebAuthnCredentialRepository) Create(ctx context.Context, credential *model.WebAuthnCredential) error {
+	return r.db.WithContext(ctx).Create(credential).Error
+}
+
+// FindByCredentialID finds a passkey by the ID chosen by the authenticator, including its user
+func (r *WebAuthnCredentialRepository) FindByCredentialID(ctx context.Context, credentialID []byte) (*model.WebAuthnCredential, error) {
+	var credential model.WebAuthnCredential
+	err := r.db.WithContext(ctx).Preload("User").Preload("User.Role").Where("credential_id = ?", credentialID).First(&credential).Error
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// CredentialIDExists checks if a passkey was already registered, including removed ones
+func (r *WebAuthnCredentialRepository) CredentialIDExists(ctx context.Context, credentialID []byte) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&model.WebAuthnCredential{}).Where("credential_id = ?", credentialID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *WebAuthnCredentialRepository) FindByUser(ctx context.Context, userID uint) ([]model.WebAuthnCredential, error) {
+	var credentials []model.WebAuthnCredential
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&credentials).Error
+	return credentials, err
+}
+
+func (r *WebAuthnCredentialRepository) FindByIDForUser(ctx context.Context, id, userID uint) (*model.WebAuthnCredential, error) {
+	var credential model.WebAuthnCredential
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&credential).Error
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+func (r *WebAuthnCredentialRepository) UpdateName(ctx context.Context, id uint, name string) error {
+	return r.db.WithContext(ctx).Model(&model.WebAuthnCredential{}).Where("id = ?", id).Update("name", name).Error
+}
+
+// RecordLogin stores the signature counter and backup state reported by the authenticator
+func (r *WebAuthnCredentialRepository) RecordLogin(ctx context.Context, id uint, signCount uint32, backupState bool) error {
+	return r.db.WithContext(ctx).Model(&model.WebAuthnCredential{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"sign_count":   signCount,
+		"backup_state": backupState,
+		"last_used_at": timezone.Now(),
+	}).Error
+}
+
+// Delete removes a passkey of the given user.
+// It returns gorm.ErrRecordNotFound if the passkey does not exist or belongs to someone else.
+func (r *WebAuthnCredentialRepository) Delete(ctx context.Context, id, userID uint) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&model.WebAuthnCredential{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
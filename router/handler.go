@@ -4,16 +4,39 @@ import (
 	"go-api/app"
 	auth "go-api/domain/auth/handler"
 	healthcheck "go-api/domain/healthcheck/handler"
+	oauth "go-api/domain/oauth/handler"
+	organization "go-api/domain/organization/handler"
+	policy "go-api/domain/policy/handler"
+	role "go-api/domain/role/handler"
+	roleservice "go-api/domain/role/service"
+	user "go-api/domain/user/handler"
+	userservice "go-api/domain/user/service"
 )
 
 type Handler struct {
 	health *healthcheck.HealthHandler
 	auth *auth.AuthHandler
+	oauth *oauth.OAuthHandler
+	organization *organization.OrganizationHandler
+	policy *policy.PolicyHandler
+	role *role.RoleHandler
+	user *user.UserHandler
 }
 
 func NewHandler(app *app.Provider) *Handler {
 	return &Handler{
 		health: healthcheck.NewHealthHandler(app),
 		auth: auth.NewAuthHandler(app),
+		oauth: oauth.NewOAuthHandler(app),
+		organization: organization.NewOrganizationHandler(app),
+		policy: policy.NewPolicyHandler(app),
+		role: role.NewRoleHandler(app),
+		user: user.NewUserHandler(app),
 	}
-}
\ No newline at end of file
+}
+
+// registerPolicies registers the authorization rules of every domain
+func registerPolicies(app *app.Provider) {
+	userservice.RegisterPolicies(app)
+	roleservice.RegisterPolicies(app)
+}
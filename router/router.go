@@ -3,12 +3,15 @@ package router
 import (
 	"go-api/app"
 	"go-api/middleware"
+	"go-api/permission"
+	"go-api/shared/constant"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 func RegisterRoutes(fiberApp *fiber.App, app *app.Provider) {
+	registerPolicies(app)
 	h := NewHandler(app)
 	
 	f := fiberApp.Group("/")
@@ -29,11 +32,124 @@ func RegisterRoutes(fiberApp *fiber.App, app *app.Provider) {
 	
 	// AUTHENTICATION ROUTES
 	auth := router.Group("/auth")
-	auth.Use(middleware.AuthRateLimitMiddleware()) // More restrictive rate limiting for auth	auth.Post("/login", authHandler.Login)
-	auth.Post("/register", h.auth.Register)
-	auth.Post("/login", h.auth.Login)
+	// More restrictive rate limiting for the sign in flows, shared by all of them
+	authLimit := middleware.AuthRateLimitMiddleware()
+	auth.Post("/register", authLimit, h.auth.Register)
+	auth.Post("/login", authLimit, h.auth.Login)
+	auth.Post("/refresh", authLimit, h.auth.Refresh)
+	auth.Post("/magic-link", authLimit, h.auth.SendMagicLink)
+	auth.Post("/magic-link/consume", authLimit, h.auth.ConsumeMagicLink)
+	auth.Get("/oidc/providers", authLimit, h.auth.ListOIDCProviders)
+	auth.Get("/oidc/:provider/authorize", authLimit, h.auth.StartOIDCLogin)
+	auth.Get("/oidc/:provider/callback", authLimit, h.auth.OIDCCallback)
+	auth.Post("/oidc/:provider/callback", authLimit, h.auth.OIDCCallback)
+	auth.Post("/password/forgot", authLimit, h.auth.ForgotPassword)
+	auth.Post("/password/reset", authLimit, h.auth.ResetPassword)
+	auth.Post("/email/verify", authLimit, h.auth.VerifyEmail)
+	auth.Post("/email/change/confirm", authLimit, h.auth.ConfirmEmailChange)
+	auth.Post("/2fa/challenge", authLimit, h.auth.CompleteTwoFactorChallenge)
+	auth.Post("/passkeys/login/begin", authLimit, h.auth.BeginPasskeyLogin)
+	auth.Post("/passkeys/login/finish", authLimit, h.auth.FinishPasskeyLogin)
 
-	protectedAuth := auth.Use(middleware.AuthMiddleware(app))
+	// API keys and OAuth tokens may read the profile within their scopes
+	account := auth.Use(middleware.AuthMiddleware(app))
+	account.Get("/me", middleware.RequireScope(constant.ScopeProfile), h.auth.Me)
+
+	// Account management is not available to API keys
+	protectedAuth := account.Use(middleware.RequireSession())
+	// Routes checking the current password again are limited per user
+	reauthLimit := middleware.ReauthRateLimitMiddleware()
 	protectedAuth.Post("/logout", h.auth.Logout)
-	protectedAuth.Post("/logout-all", h.auth.LogoutAll)
+	protectedAuth.Post("/logout-all", middleware.RequireNoImpersonation(), h.auth.LogoutAll)
+	protectedAuth.Patch("/me", h.auth.UpdateMe)
+	protectedAuth.Delete("/me", reauthLimit, middleware.RequireNoImpersonation(), h.auth.DeleteMe)
+	protectedAuth.Get("/me/export", middleware.RequireNoImpersonation(), h.auth.ExportMe)
+	protectedAuth.Post("/password", reauthLimit, middleware.RequireNoImpersonation(), h.auth.ChangePassword)
+	protectedAuth.Post("/email/resend", h.auth.ResendVerificationEmail)
+	protectedAuth.Post("/email/change", reauthLimit, middleware.RequireNoImpersonation(), h.auth.RequestEmailChange)
+	protectedAuth.Get("/sessions", h.auth.ListSessions)
+	protectedAuth.Delete("/sessions/:id", middleware.RequireNoImpersonation(), h.auth.RevokeSession)
+	protectedAuth.Post("/2fa/setup", middleware.RequireNoImpersonation(), h.auth.SetupTwoFactor)
+	protectedAuth.Post("/2fa/confirm", middleware.RequireNoImpersonation(), h.auth.ConfirmTwoFactor)
+	protectedAuth.Post("/2fa/disable", reauthLimit, middleware.RequireNoImpersonation(), h.auth.DisableTwoFactor)
+	protectedAuth.Post("/2fa/recovery-codes", reauthLimit, middleware.RequireNoImpersonation(), h.auth.RegenerateRecoveryCodes)
+	protectedAuth.Get("/api-keys", h.auth.ListAPIKeys)
+	protectedAuth.Post("/api-keys", middleware.RequireNoImpersonation(), h.auth.CreateAPIKey)
+	protectedAuth.Get("/api-keys/:id", h.auth.GetAPIKey)
+	protectedAuth.Patch("/api-keys/:id", middleware.RequireNoImpersonation(), h.auth.UpdateAPIKey)
+	protectedAuth.Delete("/api-keys/:id", h.auth.DeleteAPIKey)
+	protectedAuth.Post("/oidc/:provider/link", middleware.RequireNoImpersonation(), h.auth.LinkOIDCIdentity)
+	protectedAuth.Post("/oidc/:provider/link/callback", middleware.RequireNoImpersonation(), h.auth.CompleteOIDCLink)
+	protectedAuth.Get("/identities", h.auth.ListIdentities)
+	protectedAuth.Delete("/identities/:id", middleware.RequireNoImpersonation(), h.auth.UnlinkIdentity)
+	protectedAuth.Post("/passkeys/register/begin", middleware.RequireNoImpersonation(), h.auth.BeginPasskeyRegistration)
+	protectedAuth.Post("/passkeys/register/finish", middleware.RequireNoImpersonation(), h.auth.FinishPasskeyRegistration)
+	protectedAuth.Get("/passkeys", h.auth.ListPasskeys)
+	protectedAuth.Patch("/passkeys/:id", middleware.RequireNoImpersonation(), h.auth.UpdatePasskey)
+	protectedAuth.Delete("/passkeys/:id", middleware.RequireNoImpersonation(), h.auth.DeletePasskey)
+	protectedAuth.Post("/impersonation/stop", h.auth.StopImpersonation)
+
+	// OAUTH AUTHORIZATION SERVER ROUTES
+	oauth := router.Group("/oauth")
+	// Clients authenticate themselves on these endpoints
+	clientLimit := middleware.OAuthClientRateLimitMiddleware()
+	oauth.Post("/token", clientLimit, h.oauth.Token)
+	oauth.Post("/introspect", clientLimit, h.oauth.Introspect)
+	oauth.Post("/revoke", clientLimit, h.oauth.Revoke)
+	// Any access token, including those of the client credentials grant
+	oauth.Get("/client", middleware.OAuthClientMiddleware(app), h.oauth.CurrentClient)
+
+	// Only the user can grant access, never another client
+	protectedOAuth := oauth.Use(middleware.AuthMiddleware(app), middleware.RequireSession())
+	protectedOAuth.Get("/authorize", h.oauth.Authorize)
+	protectedOAuth.Post("/authorize", middleware.RequireNoImpersonation(), h.oauth.Consent)
+	protectedOAuth.Get("/consents", h.oauth.ListConsents)
+	protectedOAuth.Delete("/consents/:id", h.oauth.RevokeConsent)
+
+	// ORGANIZATION ROUTES
+	organizations := router.Group("/organizations")
+	// API keys and OAuth tokens reach these routes within the scopes they were granted
+	organizations.Use(middleware.AuthMiddleware(app))
+	organizations.Get("/", middleware.RequireScope(constant.ScopeRead), h.organization.ListOrganizations)
+	organizations.Post("/", middleware.RequireScope(constant.ScopeWrite), h.organization.CreateOrganization)
+
+	// Routes of one organization, the organization in the path becomes the active tenant
+	organization := organizations.Group("/:organizationID", middleware.ResolveOrganization(app))
+	organization.Get("/", middleware.RequireScope(constant.ScopeRead), h.organization.GetOrganization)
+	organization.Patch("/", middleware.RequireScope(constant.ScopeWrite), middleware.RequireOrganizationRole(constant.OrganizationRoleOwner, constant.OrganizationRoleAdmin), h.organization.UpdateOrganization)
+	organization.Delete("/", middleware.RequireScope(constant.ScopeWrite), middleware.RequireOrganizationRole(constant.OrganizationRoleOwner), h.organization.DeleteOrganization)
+	organization.Get("/members", middleware.RequireScope(constant.ScopeRead), h.organization.ListMembers)
+	organization.Post("/members", middleware.RequireScope(constant.ScopeWrite), middleware.RequireOrganizationRole(constant.OrganizationRoleOwner, constant.OrganizationRoleAdmin), h.organization.AddMember)
+	organization.Patch("/members/:userID", middleware.RequireScope(constant.ScopeWrite), middleware.RequireOrganizationRole(constant.OrganizationRoleOwner, constant.OrganizationRoleAdmin), h.organization.UpdateMember)
+	organization.Delete("/members/:userID", middleware.RequireScope(constant.ScopeWrite), h.organization.RemoveMember)
+
+	// POLICY ROUTES
+	policies := router.Group("/policies")
+	// Like the user management routes these are not available to API keys and OAuth tokens
+	policies.Use(middleware.AuthMiddleware(app), middleware.RequireSession())
+	policies.Get("/:resource/:id/actions", h.policy.AllowedActions)
+
+	// ADMIN ROUTES
+	admin := router.Group("/admin")
+	// Impersonation tokens act as the user, never with the admin's access
+	admin.Use(middleware.AuthMiddleware(app), middleware.RequireSession(), middleware.RequireNoImpersonation())
+	admin.Get("/users", middleware.RequirePermission(app, permission.UsersRead), h.user.ListUsers)
+	admin.Post("/users", middleware.RequirePermission(app, permission.UsersCreate), h.user.CreateUser)
+	admin.Get("/users/:id", middleware.RequirePermission(app, permission.UsersRead), h.user.GetUser)
+	admin.Patch("/users/:id", middleware.RequirePermission(app, permission.UsersUpdate), h.user.UpdateUser)
+	admin.Delete("/users/:id", middleware.RequirePermission(app, permission.UsersDelete), h.user.DeleteUser)
+	admin.Post("/users/:id/unlock", middleware.RequirePermission(app, permission.UsersUnlock), h.auth.UnlockUser)
+	admin.Post("/users/:id/impersonate", middleware.RequirePermission(app, permission.UsersImpersonate), h.auth.ImpersonateUser)
+	admin.Put("/users/:id/role", middleware.RequirePermission(app, permission.RolesAssign), h.role.AssignRole)
+	admin.Get("/roles", middleware.RequirePermission(app, permission.RolesRead), h.role.ListRoles)
+	admin.Post("/roles", middleware.RequirePermission(app, permission.RolesManage), h.role.CreateRole)
+	admin.Get("/roles/:id", middleware.RequirePermission(app, permission.RolesRead), h.role.GetRole)
+	admin.Patch("/roles/:id", middleware.RequirePermission(app, permission.RolesManage), h.role.UpdateRole)
+	admin.Put("/roles/:id/permissions", middleware.RequirePermission(app, permission.RolesManage), h.role.SetRolePermissions)
+	admin.Delete("/roles/:id", middleware.RequirePermission(app, permission.RolesManage), h.role.DeleteRole)
+	admin.Get("/permissions", middleware.RequirePermission(app, permission.RolesRead), h.role.ListPermissions)
+	admin.Get("/oauth/clients", middleware.RequirePermission(app, permission.OAuthClientsManage), h.oauth.ListClients)
+	admin.Post("/oauth/clients", middleware.RequirePermission(app, permission.OAuthClientsManage), h.oauth.CreateClient)
+	admin.Get("/oauth/clients/:id", middleware.RequirePermission(app, permission.OAuthClientsManage), h.oauth.GetClient)
+	admin.Delete("/oauth/clients/:id", middleware.RequirePermission(app, permission.OAuthClientsManage), h.oauth.DeleteClient)
 }
@@ -4,3 +4,21 @@ const (
 	RoleCodeAdmin = "ADMIN"
 	RoleCodeUser  = "USER"
 )
+
+// Roles of a user within an organization
+const (
+	OrganizationRoleOwner  = "owner"
+	OrganizationRoleAdmin  = "admin"
+	OrganizationRoleMember = "member"
+)
+
+// Scopes that can be granted to personal API keys and OAuth clients
+const (
+	ScopeRead    = "read"
+	ScopeWrite   = "write"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// OAuthScopes lists every scope an OAuth client can be registered for
+var OAuthScopes = []string{ScopeProfile, ScopeEmail, ScopeRead, ScopeWrite}
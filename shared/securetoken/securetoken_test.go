@@ -0,0 +1,78 @@
+package securetoken
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		size int
+	}{
+		{size: 8},
+		{size: 16},
+		{size: 32},
+	}
+
+	for _, tt := range tests {
+		token, err := Generate(tt.size)
+		if err != nil {
+			t.Fatalf("Generate(%d): %v", tt.size, err)
+		}
+		if len(token) != tt.size*2 {
+			t.Errorf("Generate(%d) returned %d characters, want %d", tt.size, len(token), tt.size*2)
+		}
+		if _, err := hex.DecodeString(token); err != nil {
+			t.Errorf("Generate(%d) returned a token that is not hex: %q", tt.size, token)
+		}
+	}
+}
+
+func TestGenerateIsRandom(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		token, err := Generate(16)
+		if err != nil {
+			t.Fatalf("Generate: %v", err)
+		}
+		if seen[token] {
+			t.Fatalf("Generate returned %q twice", token)
+		}
+		seen[token] = true
+	}
+}
+
+func TestHash(t *testing.T) {
+	tests := []struct {
+		token string
+		want  string
+	}{
+		{token: "", want: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+		{token: "abc", want: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	}
+
+	for _, tt := range tests {
+		if got := Hash(tt.token); got != tt.want {
+			t.Errorf("Hash(%q) = %s, want %s", tt.token, got, tt.want)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "token", b: "token", want: true},
+		{a: "", b: "", want: true},
+		{a: "token", b: "Token", want: false},
+		{a: "token", b: "token2", want: false},
+		{a: "token", b: "", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := Equal(tt.a, tt.b); got != tt.want {
+			t.Errorf("Equal(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
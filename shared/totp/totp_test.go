@@ -0,0 +1,132 @@
+package totp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the base32 encoding of the SHA-1 test key of RFC 6238 appendix B
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateCode(t *testing.T) {
+	// RFC 6238 appendix B, truncated to the last six digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := GenerateCode(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("GenerateCode at %d: %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("GenerateCode at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateCodeNormalizesSecret(t *testing.T) {
+	want, err := GenerateCode(rfcSecret, 1)
+	if err != nil {
+		t.Fatalf("GenerateCode: %v", err)
+	}
+	got, err := GenerateCode(" gezdgnbvgy3tqojqgezdgnbvgy3tqojq ", 1)
+	if err != nil {
+		t.Fatalf("GenerateCode with a lowercase secret: %v", err)
+	}
+	if got != want {
+		t.Fatalf("GenerateCode = %s, want %s", got, want)
+	}
+}
+
+func TestGenerateCodeRejectsInvalidSecret(t *testing.T) {
+	if _, err := GenerateCode("not base32!", 1); err == nil {
+		t.Fatal("expected an invalid secret to be rejected")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+
+	code := func(step int64) string {
+		value, err := GenerateCode(rfcSecret, step)
+		if err != nil {
+			t.Fatalf("GenerateCode: %v", err)
+		}
+		return value
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current step", code: code(current), wantStep: current, wantOK: true},
+		{name: "previous step", code: code(current - 1), wantStep: current - 1, wantOK: true},
+		{name: "next step", code: code(current + 1), wantStep: current + 1, wantOK: true},
+		{name: "outside skew", code: code(current - 2), wantOK: false},
+		{name: "spaces", code: " " + code(current)[:3] + " " + code(current)[3:] + " ", wantStep: current, wantOK: true},
+		{name: "wrong code", code: "000000", wantOK: false},
+		{name: "too short", code: code(current)[:5], wantOK: false},
+		{name: "too long", code: code(current) + "0", wantOK: false},
+		{name: "empty", code: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(rfcSecret, tt.code, now)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Fatalf("Validate(%q) = (%d, %v), want (%d, %v)", tt.code, step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret: %v", err)
+	}
+	key, err := encoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret %q is not base32: %v", secret, err)
+	}
+	if len(key) != secretSize {
+		t.Fatalf("secret holds %d bytes, want %d", len(key), secretSize)
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(URI(rfcSecret, "Go API", "jane@example.com"))
+	if err != nil {
+		t.Fatalf("parse URI: %v", err)
+	}
+
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Go API:jane@example.com" {
+		t.Fatalf("unexpected URI %q", uri)
+	}
+	want := map[string]string{
+		"secret":    rfcSecret,
+		"issuer":    "Go API",
+		"algorithm": "SHA1",
+		"digits":    "6",
+		"period":    "30",
+	}
+	query := uri.Query()
+	for key, value := range want {
+		if got := query.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+}
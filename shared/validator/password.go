@@ -0,0 +1,267 @@
+package validator
+
+import (
+	"bufio"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"go-api/config"
+	"go-api/shared/logger"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed common_passwords.txt
+var commonPasswords string
+
+// PasswordPolicy describes the rules new passwords must follow
+type PasswordPolicy struct {
+	MinLength            int
+	MaxLength            int
+	RequireLetter        bool
+	RequireUppercase     bool
+	RequireLowercase     bool
+	RequireDigit         bool
+	RequireSymbol        bool
+	MaxRepeatedChars     int  // 0 disables the check
+	DisallowPersonalInfo bool // Reject passwords containing the user's email or name
+
+	denylist       map[string]struct{} // Lowercased plain passwords
+	breachedHashes map[string]struct{} // Uppercase SHA-1 hex digests
+}
+
+var (
+	passwordPolicy     *PasswordPolicy
+	passwordPolicyOnce sync.Once
+)
+
+// NewPasswordPolicy builds the policy from the configuration. The bundled list of common
+// passwords is always loaded, the configured denylist file is added to it.
+func NewPasswordPolicy(cfg *config.Config) (*PasswordPolicy, error) {
+	policy := &PasswordPolicy{
+		MinLength:            cfg.PasswordMinLength,
+		MaxLength:            cfg.PasswordMaxLength,
+		RequireLetter:        cfg.PasswordRequireLetter,
+		RequireUppercase:     cfg.PasswordRequireUppercase,
+		RequireLowercase:     cfg.PasswordRequireLowercase,
+		RequireDigit:         cfg.PasswordRequireDigit,
+		RequireSymbol:        cfg.PasswordRequireSymbol,
+		MaxRepeatedChars:     cfg.PasswordMaxRepeatedChars,
+		DisallowPersonalInfo: cfg.PasswordDisallowPersonalInfo,
+		denylist:             make(map[string]struct{}),
+		breachedHashes:       make(map[string]struct{}),
+	}
+
+	if err := policy.loadDenylist(strings.NewReader(commonPasswords)); err != nil {
+		return nil, err
+	}
+
+	if cfg.PasswordDenylistFile != "" {
+		file, err := os.Open(cfg.PasswordDenylistFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open password denylist: %w", err)
+		}
+		defer file.Close()
+
+		if err := policy.loadDenylist(file); err != nil {
+			return nil, fmt.Errorf("failed to read password denylist: %w", err)
+		}
+	}
+
+	return policy, nil
+}
+
+// SetPasswordPolicy replaces the policy used by ValidatePasswordWithDetails
+func SetPasswordPolicy(policy *PasswordPolicy) {
+	passwordPolicyOnce.Do(func() {})
+	passwordPolicy = policy
+}
+
+// currentPasswordPolicy returns the policy set at boot, or builds it from the configuration
+// on first use. A denylist file that cannot be read leaves only the bundled list.
+func currentPasswordPolicy() *PasswordPolicy {
+	passwordPolicyOnce.Do(func() {
+		cfg := *config.Get()
+		policy, err := NewPasswordPolicy(&cfg)
+		if err != nil {
+			logger.Errorf("Failed to load password policy, using the bundled denylist only: %v", err)
+			cfg.PasswordDenylistFile = ""
+			policy, _ = NewPasswordPolicy(&cfg)
+		}
+		passwordPolicy = policy
+	})
+	return passwordPolicy
+}
+
+// loadDenylist reads one entry per line. Lines of 40 hex characters, optionally followed by
+// ":<count>" as in breach corpus downloads, are SHA-1 digests. Other lines are plain passwords.
+func (p *PasswordPolicy) loadDenylist(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if digest, _, _ := strings.Cut(line, ":"); isSHA1Hex(digest) {
+			p.breachedHashes[strings.ToUpper(digest)] = struct{}{}
+			continue
+		}
+		p.denylist[strings.ToLower(line)] = struct{}{}
+	}
+	return scanner.Err()
+}
+
+// Check returns the rules the password breaks, as messages for the password field.
+// personalInfo holds values the password must not contain, such as the email and name.
+func (p *PasswordPolicy) Check(password string, personalInfo ...string) []string {
+	if len(strings.TrimSpace(password)) == 0 {
+		return []string{"This field is required"}
+	}
+
+	var messages []string
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		messages = append(messages, fmt.Sprintf("Must be at least %d characters long", p.MinLength))
+	}
+	if length > p.MaxLength {
+		messages = append(messages, fmt.Sprintf("Must be at most %d characters long", p.MaxLength))
+	}
+
+	var hasLetter, hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, char := range password {
+		switch {
+		case unicode.IsLetter(char):
+			hasLetter = true
+			hasUpper = hasUpper || unicode.IsUpper(char)
+			hasLower = hasLower || unicode.IsLower(char)
+		case unicode.IsDigit(char):
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+
+	if p.RequireLetter && !hasLetter {
+		messages = append(messages, "Must contain at least one letter")
+	}
+	if p.RequireUppercase && !hasUpper {
+		messages = append(messages, "Must contain at least one uppercase letter")
+	}
+	if p.RequireLowercase && !hasLower {
+		messages = append(messages, "Must contain at least one lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		messages = append(messages, "Must contain at least one number")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		messages = append(messages, "Must contain at least one special character")
+	}
+
+	if p.MaxRepeatedChars > 0 && longestRun(password) > p.MaxRepeatedChars {
+		messages = append(messages, fmt.Sprintf("Cannot repeat the same character more than %d times in a row", p.MaxRepeatedChars))
+	}
+
+	if p.DisallowPersonalInfo && containsPersonalInfo(password, personalInfo) {
+		messages = append(messages, "Cannot contain your email address or name")
+	}
+
+	if p.isBreached(password) {
+		messages = append(messages, "Is too common or has appeared in a data breach, choose another password")
+	}
+
+	return messages
+}
+
+func (p *PasswordPolicy) isBreached(password string) bool {
+	if _, ok := p.denylist[strings.ToLower(password)]; ok {
+		return true
+	}
+	if len(p.breachedHashes) == 0 {
+		return false
+	}
+
+	sum := sha1.Sum([]byte(password))
+	_, ok := p.breachedHashes[strings.ToUpper(hex.EncodeToString(sum[:]))]
+	return ok
+}
+
+// longestRun returns the length of the longest run of one repeated character
+func longestRun(password string) int {
+	longest, current := 0, 0
+	var previous rune
+	for i, char := range []rune(password) {
+		if i > 0 && char == previous {
+			current++
+		} else {
+			current = 1
+		}
+		previous = char
+		longest = max(longest, current)
+	}
+	return longest
+}
+
+// containsPersonalInfo checks the password for the email, its local part and each name part.
+// Parts shorter than 3 characters are ignored to avoid rejecting unrelated passwords.
+func containsPersonalInfo(password string, personalInfo []string) bool {
+	lowered := strings.ToLower(password)
+
+	var parts []string
+	for _, value := range personalInfo {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		parts = append(parts, value)
+		if local, _, ok := strings.Cut(value, "@"); ok {
+			parts = append(parts, local)
+		}
+		parts = append(parts, strings.FieldsFunc(value, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})...)
+	}
+
+	for _, part := range parts {
+		if utf8.RuneCountInString(part) >= 3 && strings.Contains(lowered, part) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSHA1Hex(value string) bool {
+	if len(value) != 40 {
+		return false
+	}
+	_, err := hex.DecodeString(value)
+	return err == nil
+}
+
+// IsValidPassword checks if the password meets the configured policy
+func IsValidPassword(password string) bool {
+	return len(currentPasswordPolicy().Check(password)) == 0
+}
+
+// ValidatePassword validates a password against the configured policy
+func ValidatePassword(password string, personalInfo ...string) error {
+	if messages := currentPasswordPolicy().Check(password, personalInfo...); len(messages) > 0 {
+		return fmt.Errorf("password does not meet the policy: %s", strings.Join(messages, "; "))
+	}
+	return nil
+}
+
+// ValidatePasswordWithDetails validates a password against the configured policy and returns
+// the broken rules under the "password" field
+func ValidatePasswordWithDetails(password string, personalInfo ...string) map[string][]string {
+	if messages := currentPasswordPolicy().Check(password, personalInfo...); len(messages) > 0 {
+		return map[string][]string{"password": messages}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package validator
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"go-api/config"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const (
+	msgRequired  = "This field is required"
+	msgTooShort  = "Must be at least 8 characters long"
+	msgTooLong   = "Must be at most 64 characters long"
+	msgLetter    = "Must contain at least one letter"
+	msgUppercase = "Must contain at least one uppercase letter"
+	msgLowercase = "Must contain at least one lowercase letter"
+	msgDigit     = "Must contain at least one number"
+	msgSymbol    = "Must contain at least one special character"
+	msgRepeated  = "Cannot repeat the same character more than 3 times in a row"
+	msgPersonal  = "Cannot contain your email address or name"
+	msgBreached  = "Is too common or has appeared in a data breach, choose another password"
+)
+
+func testPolicyConfig() *config.Config {
+	return &config.Config{
+		PasswordMinLength:            8,
+		PasswordMaxLength:            64,
+		PasswordRequireLetter:        true,
+		PasswordRequireUppercase:     true,
+		PasswordRequireLowercase:     true,
+		PasswordRequireDigit:         true,
+		PasswordRequireSymbol:        true,
+		PasswordMaxRepeatedChars:     3,
+		PasswordDisallowPersonalInfo: true,
+	}
+}
+
+func newTestPolicy(t *testing.T, cfg *config.Config) *PasswordPolicy {
+	t.Helper()
+	policy, err := NewPasswordPolicy(cfg)
+	if err != nil {
+		t.Fatalf("NewPasswordPolicy: %v", err)
+	}
+	return policy
+}
+
+func TestPasswordPolicyCheck(t *testing.T) {
+	policy := newTestPolicy(t, testPolicyConfig())
+
+	tests := []struct {
+		name         string
+		password     string
+		personalInfo []string
+		want         []string
+	}{
+		{name: "valid", password: "Tr0ub4dor&3x", want: nil},
+		{name: "empty", password: "", want: []string{msgRequired}},
+		{name: "only spaces", password: "   ", want: []string{msgRequired}},
+		{name: "too short", password: "Ab1!xyz", want: []string{msgTooShort}},
+		{name: "too long", password: "Ab1!" + strings.Repeat("xy", 31), want: []string{msgTooLong}},
+		{name: "length counts characters not bytes", password: "Äb1!ÿzéü", want: nil},
+		{name: "no letters", password: "12345678!?", want: []string{msgLetter, msgUppercase, msgLowercase}},
+		{name: "no uppercase", password: "tr0ub4dor&3x", want: []string{msgUppercase}},
+		{name: "no lowercase", password: "TR0UB4DOR&3X", want: []string{msgLowercase}},
+		{name: "no digit", password: "Troubador&xx", want: []string{msgDigit}},
+		{name: "no symbol", password: "Tr0ub4dor3xy", want: []string{msgSymbol}},
+		{name: "repeated characters", password: "Tr0ub4aaaa&3", want: []string{msgRepeated}},
+		{name: "three repeats allowed", password: "Tr0ub4aaa&3x", want: nil},
+		{name: "email", password: "Jane.Doe@example.com1", personalInfo: []string{"jane.doe@example.com"}, want: []string{msgPersonal}},
+		{name: "email local part", password: "xX1!jane.doe", personalInfo: []string{"jane.doe@example.com"}, want: []string{msgPersonal}},
+		{name: "name part", password: "Xy1!Doeling", personalInfo: []string{"", "Jane Doe"}, want: []string{msgPersonal}},
+		{name: "short name parts ignored", password: "Tr0ub4dor&3x", personalInfo: []string{"Al Tr"}, want: nil},
+		{name: "denylisted", password: "Password", want: []string{msgDigit, msgSymbol, msgBreached}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Check(tt.password, tt.personalInfo...)
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("Check(%q) = %q, want %q", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyAllowsPersonalInfoWhenDisabled(t *testing.T) {
+	cfg := testPolicyConfig()
+	cfg.PasswordDisallowPersonalInfo = false
+	policy := newTestPolicy(t, cfg)
+
+	if got := policy.Check("Xy1!Doeling", "Jane Doe"); got != nil {
+		t.Fatalf("Check = %q, want no messages", got)
+	}
+}
+
+func TestPasswordPolicyDenylistFile(t *testing.T) {
+	breached := "Br3ached!Pass"
+	sum := sha1.Sum([]byte(breached))
+
+	path := filepath.Join(t.TempDir(), "denylist.txt")
+	content := strings.Join([]string{
+		"# comment",
+		"",
+		"Company#2024",
+		strings.ToLower(hex.EncodeToString(sum[:])) + ":42",
+	}, "\n")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write denylist: %v", err)
+	}
+
+	cfg := testPolicyConfig()
+	cfg.PasswordDenylistFile = path
+	policy := newTestPolicy(t, cfg)
+
+	tests := []struct {
+		password string
+		want     []string
+	}{
+		{password: "company#2024", want: []string{msgUppercase, msgBreached}},
+		{password: "Company#2024", want: []string{msgBreached}},
+		{password: breached, want: []string{msgBreached}},
+		{password: "Tr0ub4dor&3x", want: nil},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Check(tt.password); !slices.Equal(got, tt.want) {
+			t.Errorf("Check(%q) = %q, want %q", tt.password, got, tt.want)
+		}
+	}
+}
+
+func TestPasswordPolicyMissingDenylistFile(t *testing.T) {
+	cfg := testPolicyConfig()
+	cfg.PasswordDenylistFile = filepath.Join(t.TempDir(), "missing.txt")
+
+	if _, err := NewPasswordPolicy(cfg); err == nil {
+		t.Fatal("expected a missing denylist file to be reported")
+	}
+}
+
+func TestLongestRun(t *testing.T) {
+	tests := []struct {
+		password string
+		want     int
+	}{
+		{password: "", want: 0},
+		{password: "a", want: 1},
+		{password: "abc", want: 1},
+		{password: "aabbbc", want: 3},
+		{password: "ääää", want: 4},
+	}
+
+	for _, tt := range tests {
+		if got := longestRun(tt.password); got != tt.want {
+			t.Errorf("longestRun(%q) = %d, want %d", tt.password, got, tt.want)
+		}
+	}
+}
@@ -67,6 +67,8 @@ func formatValidationError(fe validator.FieldError) string {
 	switch fe.Tag() {
 	case "required":
 		return "This field is required"
+	case "required_without":
+		return fmt.Sprintf("This field is required when %s is not provided", strings.ToLower(fe.Param()))
 	case "email":
 		return "Must be a valid email address"
 	case "min":
@@ -107,93 +109,3 @@ func IsValidEmail(email string) bool {
 	err := validate.Var(email, "required,email")
 	return err == nil
 }
-
-// IsValidPassword checks if the password meets minimum requirements
-func IsValidPassword(password string) bool {
-	err := validate.Var(password, "required,min=8")
-	return err == nil
-}
-
-// ValidatePassword validates password with custom rules
-func ValidatePassword(password string) error {
-	if len(strings.TrimSpace(password)) == 0 {
-		return fmt.Errorf("password is required")
-	}
-
-	if len(password) < 8 {
-		return fmt.Errorf("password must be at least 8 characters long")
-	}
-
-	if len(password) > 100 {
-		return fmt.Errorf("password must be at most 100 characters long")
-	}
-
-	// Check for at least one letter and one number (optional enhancement)
-	hasLetter := false
-	hasNumber := false
-
-	for _, char := range password {
-		if (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') {
-			hasLetter = true
-		}
-		if char >= '0' && char <= '9' {
-			hasNumber = true
-		}
-	}
-
-	if !hasLetter {
-		return fmt.Errorf("password must contain at least one letter")
-	}
-
-	if !hasNumber {
-		return fmt.Errorf("password must contain at least one number")
-	}
-
-	return nil
-}
-
-// ValidatePasswordWithDetails validates password and returns structured error
-func ValidatePasswordWithDetails(password string) map[string][]string {
-	var messages []string
-
-	if len(strings.TrimSpace(password)) == 0 {
-		messages = append(messages, "This field is required")
-	} else {
-		if len(password) < 8 {
-			messages = append(messages, "Must be at least 8 characters long")
-		}
-
-		if len(password) > 100 {
-			messages = append(messages, "Must be at most 100 characters long")
-		}
-
-		// Check for at least one letter and one number
-		hasLetter := false
-		hasNumber := false
-
-		for _, char := range password {
-			if (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') {
-				hasLetter = true
-			}
-			if char >= '0' && char <= '9' {
-				hasNumber = true
-			}
-		}
-
-		if !hasLetter {
-			messages = append(messages, "Must contain at least one letter")
-		}
-
-		if !hasNumber {
-			messages = append(messages, "Must contain at least one number")
-		}
-	}
-
-	if len(messages) > 0 {
-		errors := make(map[string][]string)
-		errors["password"] = messages
-		return errors
-	}
-
-	return nil
-}
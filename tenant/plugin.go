@@ -0,0 +1,97 @@
+package tenant
+
+import (
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const column = "organization_id"
+
+// ErrNoOrganization is returned when a scoped model is used without an active organization
+var ErrNoOrganization = errors.New("no active organization for tenant scoped query")
+
+// Plugin scopes every statement on a Scoped model to the organization in the statement
+// context: queries, updates and deletes are filtered and created rows are assigned to it.
+// Statements without an organization fail, unless the context is marked Unscoped.
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "tenant"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("tenant:create", assignOrganization); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", filterOrganization); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", filterOrganization); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:delete", filterOrganization); err != nil {
+		return err
+	}
+	return callbacks.Row().Before("gorm:row").Register("tenant:row", filterOrganization)
+}
+
+// activeOrganization returns the organization a statement is scoped to, ok is false when the
+// statement does not need scoping
+func activeOrganization(db *gorm.DB) (organizationID uint, ok bool) {
+	if db.Error != nil || db.Statement.Schema == nil || isUnscoped(db.Statement.Context) {
+		return 0, false
+	}
+	if _, scoped := reflect.New(db.Statement.Schema.ModelType).Interface().(Scoped); !scoped {
+		return 0, false
+	}
+
+	organizationID, ok = OrganizationID(db.Statement.Context)
+	if !ok {
+		db.AddError(ErrNoOrganization)
+		return 0, false
+	}
+	return organizationID, true
+}
+
+func filterOrganization(db *gorm.DB) {
+	organizationID, ok := activeOrganization(db)
+	if !ok {
+		return
+	}
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: db.Statement.Table, Name: column}, Value: organizationID},
+	}})
+}
+
+func assignOrganization(db *gorm.DB) {
+	organizationID, ok := activeOrganization(db)
+	if !ok {
+		return
+	}
+
+	field := db.Statement.Schema.LookUpField(column)
+	if field == nil {
+		return
+	}
+
+	// Rows are always created in the active organization, whatever the caller set
+	rows := db.Statement.ReflectValue
+	switch rows.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rows.Len(); i++ {
+			if err := field.Set(db.Statement.Context, reflect.Indirect(rows.Index(i)), organizationID); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+	case reflect.Struct:
+		if err := field.Set(db.Statement.Context, rows, organizationID); err != nil {
+			db.AddError(err)
+		}
+	}
+}
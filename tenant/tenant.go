@@ -0,0 +1,37 @@
+// Package tenant carries the active organization through request contexts and scopes
+// queries on organization-owned models to it.
+package tenant
+
+import "context"
+
+type organizationKey struct{}
+
+type unscopedKey struct{}
+
+// Scoped is implemented by models whose rows belong to one organization, through an
+// organization_id column. Embed model.TenantAttributes to implement it.
+type Scoped interface {
+	TenantScoped()
+}
+
+// WithOrganization returns a context in which queries on scoped models only see the rows of the organization
+func WithOrganization(ctx context.Context, organizationID uint) context.Context {
+	return context.WithValue(ctx, organizationKey{}, organizationID)
+}
+
+// OrganizationID returns the active organization of ctx
+func OrganizationID(ctx context.Context) (uint, bool) {
+	organizationID, ok := ctx.Value(organizationKey{}).(uint)
+	return organizationID, ok && organizationID != 0
+}
+
+// Unscoped returns a context in which queries on scoped models see the rows of every
+// organization. Only use it for lookups across organizations, such as resolving memberships.
+func Unscoped(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedKey{}, true)
+}
+
+func isUnscoped(ctx context.Context) bool {
+	unscoped, _ := ctx.Value(unscopedKey{}).(bool)
+	return unscoped
+}